@@ -1,25 +1,48 @@
 package bayes
 
 import (
+	"compress/gzip"
 	"encoding/gob"
 	"io"
 	"math"
 	"os"
+	"sort"
 	"sync"
+
+	"github.com/bububa/jiagu/classify/selection"
+)
+
+// Variant 朴素贝叶斯模型类型
+type Variant = string
+
+const (
+	// Multinomial_Variant 多项式朴素贝叶斯
+	Multinomial_Variant Variant = "multinomial"
+	// Bernoulli_Variant 伯努利朴素贝叶斯, 需要使用Learn训练以记录文档频率
+	Bernoulli_Variant Variant = "bernoulli"
+	// Complement_Variant 补集朴素贝叶斯, 适合类别不均衡的数据
+	Complement_Variant Variant = "complement"
 )
 
 // Bayes bayes文本分类
 type Bayes struct {
 	total      float64
+	docs       float64
+	variant    Variant
+	alpha      float64
 	categories map[string]int
 	probes     []*Probe
+	vocab      map[string]int       // 词 -> 包含该词的分类数量
+	selected   selection.Vocabulary // 特征选择的词表, 分类时忽略词表以外的词, nil表示不限制
 	locker     *sync.RWMutex
 }
 
 // New 新建Bayes
 func New() *Bayes {
 	return &Bayes{
+		variant:    Multinomial_Variant,
 		categories: make(map[string]int),
+		vocab:      make(map[string]int),
 		locker:     new(sync.RWMutex),
 	}
 }
@@ -27,25 +50,75 @@ func New() *Bayes {
 // NewFromModel 从model新建Bayes
 func NewFromModel(model Model) *Bayes {
 	l := len(model.Data)
-	categories := make(map[string]int, l)
-	probes := make([]*Probe, 0, l)
-	var idx int
+	b := New()
+	b.total = model.Total
+	b.docs = model.Docs
+	b.alpha = model.Alpha
+	if model.Variant != "" {
+		b.variant = model.Variant
+	}
+	if len(model.Vocabulary) > 0 {
+		b.selected = make(selection.Vocabulary, len(model.Vocabulary))
+		for _, term := range model.Vocabulary {
+			b.selected[term] = struct{}{}
+		}
+	}
+	b.probes = make([]*Probe, 0, l)
 	for cat, probeData := range model.Data {
-		categories[cat] = idx
+		b.categories[cat] = len(b.probes)
 		probe := NewProbe()
 		probe.Category = cat
 		probe.Total = probeData.Total
-		probe.Data = probeData.Data
 		probe.None = probeData.None
-		probes = append(probes, probe)
-		idx++
-	}
-	return &Bayes{
-		total:      model.Total,
-		categories: categories,
-		probes:     probes,
-		locker:     new(sync.RWMutex),
+		probe.Docs = probeData.Docs
+		if probeData.Data != nil {
+			probe.Data = probeData.Data
+		}
+		if probeData.DocFreq != nil {
+			probe.DocFreq = probeData.DocFreq
+		}
+		for key := range probe.Data {
+			b.vocab[key]++
+		}
+		b.probes = append(b.probes, probe)
 	}
+	return b
+}
+
+// SetVocabulary 设置特征选择的词表, 分类时忽略词表以外的词, nil表示不限制
+func (b *Bayes) SetVocabulary(vocab selection.Vocabulary) {
+	b.locker.Lock()
+	b.selected = vocab
+	b.locker.Unlock()
+}
+
+// Vocabulary 获取特征选择的词表
+func (b *Bayes) Vocabulary() selection.Vocabulary {
+	b.locker.RLock()
+	defer b.locker.RUnlock()
+	return b.selected
+}
+
+// SetVariant 设置模型类型
+func (b *Bayes) SetVariant(variant Variant) {
+	b.locker.Lock()
+	b.variant = variant
+	b.locker.Unlock()
+}
+
+// Variant 获取模型类型
+func (b *Bayes) Variant() Variant {
+	b.locker.RLock()
+	defer b.locker.RUnlock()
+	return b.variant
+}
+
+// SetSmoothing 设置平滑参数, alpha=1为Laplace平滑, 0<alpha<1为Lidstone平滑
+// alpha<=0时多项式模型使用分类内加一平滑(与原python版本一致), 其他模型使用Laplace平滑
+func (b *Bayes) SetSmoothing(alpha float64) {
+	b.locker.Lock()
+	b.alpha = alpha
+	b.locker.Unlock()
 }
 
 // NewFromReader 从io.Reader创建Bayes
@@ -64,6 +137,21 @@ func (b *Bayes) Save(w io.Writer) error {
 	return gob.NewEncoder(w).Encode(model)
 }
 
+// SaveGzipFile save gzip compressed model to a file, the format SentimentInstance loads
+func (b *Bayes) SaveGzipFile(loc string) error {
+	fd, err := os.OpenFile(loc, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	gw := gzip.NewWriter(fd)
+	if err := b.Save(gw); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
 // SaveFile save model to a file
 func (b *Bayes) SaveFile(loc string) error {
 	fd, err := os.OpenFile(loc, os.O_WRONLY|os.O_CREATE, 0666)
@@ -76,10 +164,19 @@ func (b *Bayes) SaveFile(loc string) error {
 
 // ToModel 转换为模型
 func (b *Bayes) ToModel() Model {
+	b.locker.RLock()
 	model := Model{
-		Total: b.GetSum(),
-		Data:  make(map[string]Probe, b.Len()),
+		Total:   b.total,
+		Docs:    b.docs,
+		Variant: b.variant,
+		Alpha:   b.alpha,
+		Data:    make(map[string]Probe, len(b.probes)),
+	}
+	for term := range b.selected {
+		model.Vocabulary = append(model.Vocabulary, term)
 	}
+	b.locker.RUnlock()
+	sort.Strings(model.Vocabulary)
 	iter := b.Iter()
 	for probe := range iter {
 		model.Data[probe.Category] = *probe
@@ -97,20 +194,101 @@ func (b *Bayes) GetCategory(cat string) *Probe {
 	return nil
 }
 
-// AddWords 添加keywords
+// Exists 判断词是否在模型词表中
+func (b *Bayes) Exists(word string) bool {
+	b.locker.RLock()
+	defer b.locker.RUnlock()
+	_, found := b.vocab[word]
+	return found
+}
+
+// AddWords 添加keywords, 与Learn相同计为一个样本
 func (b *Bayes) AddWords(cat string, words map[string]float64) {
-	probe := b.GetCategory(cat)
-	if probe != nil {
-		probe.AddWords(words)
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	probe := b.category(cat)
+	b.addVocab(probe, words)
+	before := probe.GetSum()
+	probe.Learn(words)
+	b.total += probe.GetSum() - before
+	b.docs++
+}
+
+// Learn 增量学习一个样本
+func (b *Bayes) Learn(cat string, words []string) {
+	counts := wordCounts(words)
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	probe := b.category(cat)
+	b.addVocab(probe, counts)
+	before := probe.GetSum()
+	probe.Learn(counts)
+	b.total += probe.GetSum() - before
+	b.docs++
+}
+
+// Forget 移除一个已学习的样本
+func (b *Bayes) Forget(cat string, words []string) {
+	counts := wordCounts(words)
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	idx, found := b.categories[cat]
+	if !found {
 		return
 	}
-	b.locker.Lock()
-	probe = NewProbe()
-	probe.AddWords(words)
-	lastIdx := len(b.probes)
-	b.categories[cat] = lastIdx
+	probe := b.probes[idx]
+	// 只有该分类原有的词才计入词表
+	learned := make([]string, 0, len(counts))
+	for key := range counts {
+		if probe.Exists(key) {
+			learned = append(learned, key)
+		}
+	}
+	before := probe.GetSum()
+	probe.Forget(counts)
+	b.total += probe.GetSum() - before
+	if b.docs > 0 {
+		b.docs--
+	}
+	for _, key := range learned {
+		if probe.Exists(key) {
+			continue
+		}
+		if n := b.vocab[key]; n <= 1 {
+			delete(b.vocab, key)
+		} else {
+			b.vocab[key] = n - 1
+		}
+	}
+}
+
+// category 获取或新建分类, 调用方需持有写锁
+func (b *Bayes) category(cat string) *Probe {
+	if idx, found := b.categories[cat]; found {
+		return b.probes[idx]
+	}
+	probe := NewProbe()
+	probe.Category = cat
+	b.categories[cat] = len(b.probes)
 	b.probes = append(b.probes, probe)
-	b.locker.Unlock()
+	return probe
+}
+
+// addVocab 更新词表, 调用方需持有写锁
+func (b *Bayes) addVocab(probe *Probe, words map[string]float64) {
+	for key := range words {
+		if !probe.Exists(key) {
+			b.vocab[key]++
+		}
+	}
+}
+
+func wordCounts(words []string) map[string]float64 {
+	counts := make(map[string]float64, len(words))
+	for _, w := range words {
+		counts[w]++
+	}
+	return counts
 }
 
 // GetSum 获取total
@@ -148,32 +326,46 @@ func (b *Bayes) Iter() <-chan *Probe {
 
 // Classify 情感分析分类
 func (b *Bayes) Classify(words []string) (string, float64) {
-	tmp := make(map[string]float64, b.Len())
-	iter := b.Iter()
-	for probe := range iter {
-		category := probe.Category
-		tmp[category] = math.Log(probe.GetSum()) / math.Log(b.GetSum())
-		for _, w := range words {
-			tmp[category] += math.Log(probe.Freq(w))
-		}
+	probs := b.ClassifyProba(words)
+	if len(probs) == 0 {
+		return "", 0
 	}
-	var (
-		category string
-		prob     float64
-	)
-	iter = b.Iter()
-	for probe1 := range iter {
-		var now float64
-		iter2 := b.Iter()
-		for probe2 := range iter2 {
-			now += math.Exp(tmp[probe2.Category] - tmp[probe1.Category])
-		}
-		if now > 0 {
-			now = 1 / now
+	return probs[0].Category, probs[0].Value
+}
+
+// ClassifyProba 计算全部分类的概率, 按概率降序排列
+func (b *Bayes) ClassifyProba(words []string) []Prob {
+	scores := b.logScores(words)
+	probs := make([]Prob, 0, len(scores))
+	maxScore := math.Inf(-1)
+	for cat, score := range scores {
+		probs = append(probs, Prob{Category: cat, Value: score})
+		if score > maxScore {
+			maxScore = score
 		}
-		if now > prob {
-			category, prob = probe1.Category, now
+	}
+	// log-sum-exp, 得分均为-Inf时各分类概率相同
+	var sum float64
+	for idx, p := range probs {
+		if math.IsInf(maxScore, -1) {
+			probs[idx].Value = 1
+		} else {
+			probs[idx].Value = math.Exp(p.Value - maxScore)
 		}
+		sum += probs[idx].Value
+	}
+	for idx := range probs {
+		probs[idx].Value /= sum
+	}
+	sort.Sort(sort.Reverse(ProbSlice(probs)))
+	return probs
+}
+
+// ClassifyTopN 返回概率最高的n个分类
+func (b *Bayes) ClassifyTopN(words []string, n int) []Prob {
+	probs := b.ClassifyProba(words)
+	if n >= 0 && len(probs) > n {
+		probs = probs[:n]
 	}
-	return category, prob
+	return probs
 }
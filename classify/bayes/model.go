@@ -2,6 +2,10 @@ package bayes
 
 // Model bayes model for saving
 type Model struct {
-	Total float64          `json:"total,omitempty"`
-	Data  map[string]Probe `json:"d,omitempty"`
+	Total      float64          `json:"total,omitempty"`
+	Docs       float64          `json:"docs,omitempty"`
+	Variant    Variant          `json:"variant,omitempty"`
+	Alpha      float64          `json:"alpha,omitempty"`
+	Data       map[string]Probe `json:"d,omitempty"`
+	Vocabulary []string         `json:"vocabulary,omitempty"` // 特征选择的词表, 为空表示不限制
 }
@@ -0,0 +1,9 @@
+package bayes
+
+import "github.com/bububa/jiagu/classify"
+
+// Prob 分类概率
+type Prob = classify.Prob
+
+// ProbSlice Prob array
+type ProbSlice = classify.ProbSlice
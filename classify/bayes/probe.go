@@ -5,20 +5,24 @@ import (
 )
 
 // Probe 单个分类内词频
+// Data中的词频包含加一平滑的初始值1, Total为Data之和
 type Probe struct {
 	Category string             `json:"-"`
-	Total    float64            `json:'total,omitempty'`
+	Total    float64            `json:"total,omitempty"`
 	Data     map[string]float64 `json:"d,omitempty"`
 	None     float64            `json:"none,omitempty"`
+	Docs     float64            `json:"docs,omitempty"` // 样本数量
+	DocFreq  map[string]float64 `json:"df,omitempty"`   // 包含该词的样本数量
 	locker   *sync.RWMutex
 }
 
 // NewProbe 新建Probe
 func NewProbe() *Probe {
 	return &Probe{
-		Data:   make(map[string]float64),
-		None:   1,
-		locker: new(sync.RWMutex),
+		Data:    make(map[string]float64),
+		DocFreq: make(map[string]float64),
+		None:    1,
+		locker:  new(sync.RWMutex),
 	}
 }
 
@@ -46,27 +50,49 @@ func (a *Probe) GetSum() float64 {
 	return total
 }
 
-// Freq 获取key词频
+// Count 获取key的原始词频(不含平滑初始值)
+func (a *Probe) Count(key string) float64 {
+	a.locker.RLock()
+	defer a.locker.RUnlock()
+	if val, found := a.Data[key]; found {
+		return val - 1
+	}
+	return 0
+}
+
+// RawSum 获取原始总词频(不含平滑初始值)
+func (a *Probe) RawSum() float64 {
+	a.locker.RLock()
+	defer a.locker.RUnlock()
+	return a.Total - float64(len(a.Data))
+}
+
+// Freq 获取key词频, 未出现的key使用None值
 func (a *Probe) Freq(key string) float64 {
-	return a.Get(key) / a.GetSum()
+	a.locker.RLock()
+	defer a.locker.RUnlock()
+	val, found := a.Data[key]
+	if !found {
+		val = a.None
+	}
+	return val / a.Total
 }
 
 func (a *Probe) AddWords(words map[string]float64) {
 	a.locker.Lock()
 	defer a.locker.Unlock()
 	for key, value := range words {
-		a.Total += value
-		if _, found := a.Data[key]; !found {
-			a.Data[key] = 1
-			a.Total++
-		}
-		a.Data[key] += value
+		a.add(key, value)
 	}
 }
 
 func (a *Probe) Add(key string, value float64) {
 	a.locker.Lock()
 	defer a.locker.Unlock()
+	a.add(key, value)
+}
+
+func (a *Probe) add(key string, value float64) {
 	a.Total += value
 	if _, found := a.Data[key]; !found {
 		a.Data[key] = 1
@@ -74,3 +100,52 @@ func (a *Probe) Add(key string, value float64) {
 	}
 	a.Data[key] += value
 }
+
+// Learn 添加一个样本的词频, 同时更新样本数量及文档频率
+func (a *Probe) Learn(words map[string]float64) {
+	a.locker.Lock()
+	defer a.locker.Unlock()
+	a.Docs++
+	for key, value := range words {
+		a.add(key, value)
+		a.DocFreq[key]++
+	}
+}
+
+// Forget 移除一个已学习样本的词频
+func (a *Probe) Forget(words map[string]float64) {
+	a.locker.Lock()
+	defer a.locker.Unlock()
+	if a.Docs > 0 {
+		a.Docs--
+	}
+	for key, value := range words {
+		val, found := a.Data[key]
+		if !found {
+			continue
+		}
+		if val-value <= 1 {
+			a.Total -= val
+			delete(a.Data, key)
+		} else {
+			a.Total -= value
+			a.Data[key] -= value
+		}
+		if df := a.DocFreq[key]; df <= 1 {
+			delete(a.DocFreq, key)
+		} else {
+			a.DocFreq[key] = df - 1
+		}
+	}
+}
+
+// Keys 获取全部key
+func (a *Probe) Keys() []string {
+	a.locker.RLock()
+	defer a.locker.RUnlock()
+	keys := make([]string, 0, len(a.Data))
+	for key := range a.Data {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,137 @@
+package bayes
+
+import (
+	"math"
+)
+
+// logScores 计算各分类的对数得分
+func (b *Bayes) logScores(words []string) map[string]float64 {
+	b.locker.RLock()
+	defer b.locker.RUnlock()
+	scores := make(map[string]float64, len(b.probes))
+	if len(b.probes) == 0 {
+		return scores
+	}
+	if b.selected != nil {
+		words = b.selected.Filter(words)
+	}
+	switch b.variant {
+	case Bernoulli_Variant:
+		if b.docs > 0 {
+			b.bernoulliScores(words, scores)
+			return scores
+		}
+	case Complement_Variant:
+		b.complementScores(words, scores)
+		return scores
+	}
+	b.multinomialScores(words, scores)
+	return scores
+}
+
+// logPrior 分类先验概率, 全部分类都有样本数量时按样本数量计算, 否则按词频计算
+func (b *Bayes) logPrior(probe *Probe, byDocs bool) float64 {
+	if byDocs {
+		return math.Log(probe.Docs) - math.Log(b.docs)
+	}
+	return math.Log(probe.GetSum()) - math.Log(b.total)
+}
+
+// docPrior 是否全部分类都有样本数量, 调用方需持有读锁
+func (b *Bayes) docPrior() bool {
+	if b.docs <= 0 {
+		return false
+	}
+	for _, probe := range b.probes {
+		if probe.Docs <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Bayes) smoothing() float64 {
+	if b.alpha > 0 {
+		return b.alpha
+	}
+	return 1
+}
+
+func (b *Bayes) multinomialScores(words []string, scores map[string]float64) {
+	var (
+		vocabSize = float64(len(b.vocab))
+		byDocs    = b.docPrior()
+	)
+	for cat, idx := range b.categories {
+		probe := b.probes[idx]
+		score := b.logPrior(probe, byDocs)
+		if b.alpha <= 0 {
+			for _, w := range words {
+				score += math.Log(probe.Freq(w))
+			}
+		} else {
+			denominator := math.Log(probe.RawSum() + b.alpha*vocabSize)
+			for _, w := range words {
+				score += math.Log(probe.Count(w)+b.alpha) - denominator
+			}
+		}
+		scores[cat] = score
+	}
+}
+
+// bernoulliScores 伯努利模型, 词表中未出现在样本中的词同样参与计算
+func (b *Bayes) bernoulliScores(words []string, scores map[string]float64) {
+	var (
+		alpha     = b.smoothing()
+		vocabSize = float64(len(b.vocab))
+		present   = make(map[string]struct{}, len(words))
+		byDocs    = b.docPrior()
+	)
+	for _, w := range words {
+		if _, found := b.vocab[w]; found {
+			present[w] = struct{}{}
+		}
+	}
+	for cat, idx := range b.categories {
+		probe := b.probes[idx]
+		probe.locker.RLock()
+		denominator := probe.Docs + 2*alpha
+		// 不包含任何词时的对数似然
+		unseen := alpha / denominator
+		score := b.logPrior(probe, byDocs) + (vocabSize-float64(len(probe.DocFreq)))*math.Log(1-unseen)
+		for _, df := range probe.DocFreq {
+			score += math.Log(1 - (df+alpha)/denominator)
+		}
+		for w := range present {
+			p := (probe.DocFreq[w] + alpha) / denominator
+			score += math.Log(p) - math.Log(1-p)
+		}
+		probe.locker.RUnlock()
+		scores[cat] = score
+	}
+}
+
+// complementScores 补集模型, 使用其他分类的词频估计参数
+func (b *Bayes) complementScores(words []string, scores map[string]float64) {
+	var (
+		alpha     = b.smoothing()
+		vocabSize = float64(len(b.vocab))
+		rawTotal  float64
+		counts    = make(map[string]float64, len(words))
+	)
+	for _, probe := range b.probes {
+		rawTotal += probe.RawSum()
+		for _, w := range words {
+			counts[w] += probe.Count(w)
+		}
+	}
+	for cat, idx := range b.categories {
+		probe := b.probes[idx]
+		denominator := math.Log(rawTotal - probe.RawSum() + alpha*vocabSize)
+		var score float64
+		for _, w := range words {
+			score -= math.Log(counts[w]-probe.Count(w)+alpha) - denominator
+		}
+		scores[cat] = score
+	}
+}
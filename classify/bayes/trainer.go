@@ -0,0 +1,74 @@
+package bayes
+
+import (
+	"github.com/bububa/jiagu/classify"
+	"github.com/bububa/jiagu/classify/selection"
+	"github.com/bububa/jiagu/segment"
+	"github.com/bububa/jiagu/stopwords"
+)
+
+// Trainer 从标注文本训练Bayes模型
+type Trainer struct {
+	tokenizer classify.Tokenizer
+	variant   Variant
+	alpha     float64
+	selection selection.Method
+	topK      int
+	minDF     int
+}
+
+// NewTrainer 新建Trainer
+func NewTrainer(seg *segment.Segment, stwords *stopwords.Stopwords) *Trainer {
+	return &Trainer{
+		tokenizer: classify.NewTokenizer(seg, stwords),
+		variant:   Multinomial_Variant,
+	}
+}
+
+// SetVariant 设置训练的模型类型
+func (t *Trainer) SetVariant(variant Variant) {
+	t.variant = variant
+}
+
+// SetSmoothing 设置训练的模型平滑参数
+func (t *Trainer) SetSmoothing(alpha float64) {
+	t.alpha = alpha
+}
+
+// SetSelection 设置特征选择方法, 仅使用得分最高的topK个词训练, 忽略文档频率小于minDF的词
+func (t *Trainer) SetSelection(method selection.Method, topK int, minDF int) {
+	t.selection = method
+	t.topK = topK
+	t.minDF = minDF
+}
+
+// Tokenize 分词并去除停用词
+func (t *Trainer) Tokenize(txt string) []string {
+	return t.tokenizer(txt)
+}
+
+// Train 训练模型, 未分词的样本使用Tokenize分词
+func (t *Trainer) Train(samples []classify.Sample) *Bayes {
+	classify.Tokenize(samples, t.Tokenize)
+	model := New()
+	model.SetVariant(t.variant)
+	model.SetSmoothing(t.alpha)
+	var vocab selection.Vocabulary
+	if t.selection != "" {
+		vocab = selection.Select(samples, t.selection, t.topK, t.minDF)
+		model.SetVocabulary(vocab)
+	}
+	for _, sample := range samples {
+		words := sample.Words
+		if vocab != nil {
+			words = vocab.Filter(words)
+		}
+		model.Learn(sample.Label, words)
+	}
+	return model
+}
+
+// Eval 计算模型在样本上的准确率
+func (t *Trainer) Eval(model *Bayes, samples []classify.Sample) float64 {
+	return classify.Eval(model, samples, t.tokenizer)
+}
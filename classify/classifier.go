@@ -0,0 +1,24 @@
+package classify
+
+// Classifier 文本分类器, 返回概率最高的分类及其概率
+type Classifier interface {
+	Classify(words []string) (string, float64)
+}
+
+// TrainFunc 使用样本训练分类器
+type TrainFunc = func(samples []Sample) Classifier
+
+// Eval 计算分类器在样本上的准确率, 未分词的样本使用tokenizer分词
+func Eval(model Classifier, samples []Sample, tokenizer Tokenizer) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	Tokenize(samples, tokenizer)
+	var correct float64
+	for _, sample := range samples {
+		if cat, _ := model.Classify(sample.Words); cat == sample.Label {
+			correct++
+		}
+	}
+	return correct / float64(len(samples))
+}
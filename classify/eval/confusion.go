@@ -0,0 +1,140 @@
+package eval
+
+import "sort"
+
+// ConfusionMatrix 混淆矩阵, 行为真实分类, 列为预测分类
+type ConfusionMatrix struct {
+	labels []string
+	index  map[string]int
+	counts map[int]map[int]int
+	total  int
+}
+
+// NewConfusionMatrix 新建ConfusionMatrix
+func NewConfusionMatrix() *ConfusionMatrix {
+	return &ConfusionMatrix{
+		index:  make(map[string]int),
+		counts: make(map[int]map[int]int),
+	}
+}
+
+// Add 添加一次预测结果
+func (m *ConfusionMatrix) Add(actual string, predicted string) {
+	a, p := m.label(actual), m.label(predicted)
+	row, found := m.counts[a]
+	if !found {
+		row = make(map[int]int)
+		m.counts[a] = row
+	}
+	row[p]++
+	m.total++
+}
+
+// Merge 合并另一个混淆矩阵
+func (m *ConfusionMatrix) Merge(other *ConfusionMatrix) {
+	for a, row := range other.counts {
+		for p, count := range row {
+			actual, predicted := m.label(other.labels[a]), m.label(other.labels[p])
+			if _, found := m.counts[actual]; !found {
+				m.counts[actual] = make(map[int]int)
+			}
+			m.counts[actual][predicted] += count
+			m.total += count
+		}
+	}
+}
+
+func (m *ConfusionMatrix) label(label string) int {
+	idx, found := m.index[label]
+	if !found {
+		idx = len(m.labels)
+		m.index[label] = idx
+		m.labels = append(m.labels, label)
+	}
+	return idx
+}
+
+// Labels 全部分类, 按名称排序
+func (m *ConfusionMatrix) Labels() []string {
+	ret := make([]string, len(m.labels))
+	copy(ret, m.labels)
+	sort.Strings(ret)
+	return ret
+}
+
+// Count 真实分类为actual且预测为predicted的数量
+func (m *ConfusionMatrix) Count(actual string, predicted string) int {
+	a, found := m.index[actual]
+	if !found {
+		return 0
+	}
+	p, found := m.index[predicted]
+	if !found {
+		return 0
+	}
+	return m.counts[a][p]
+}
+
+// Total 预测总数
+func (m *ConfusionMatrix) Total() int {
+	return m.total
+}
+
+// Correct 预测正确的数量
+func (m *ConfusionMatrix) Correct() int {
+	var correct int
+	for a, row := range m.counts {
+		correct += row[a]
+	}
+	return correct
+}
+
+// Accuracy 准确率
+func (m *ConfusionMatrix) Accuracy() float64 {
+	if m.total == 0 {
+		return 0
+	}
+	return float64(m.Correct()) / float64(m.total)
+}
+
+// Metrics 计算分类的精确率、召回率及F1
+func (m *ConfusionMatrix) Metrics(label string) Metrics {
+	ret := Metrics{Label: label}
+	idx, found := m.index[label]
+	if !found {
+		return ret
+	}
+	var tp, predicted, actual int
+	for a, row := range m.counts {
+		for p, count := range row {
+			if a == idx {
+				actual += count
+			}
+			if p == idx {
+				predicted += count
+				if a == idx {
+					tp += count
+				}
+			}
+		}
+	}
+	ret.Support = actual
+	ret.Precision = ratio(tp, predicted)
+	ret.Recall = ratio(tp, actual)
+	ret.F1 = f1(ret.Precision, ret.Recall)
+	return ret
+}
+
+func ratio(a int, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func f1(precision float64, recall float64) float64 {
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
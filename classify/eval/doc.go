@@ -0,0 +1,2 @@
+// Package eval 文本分类评估, 包含混淆矩阵、精确率/召回率/F1及分层k折交叉验证
+package eval
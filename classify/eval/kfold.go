@@ -0,0 +1,63 @@
+package eval
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/bububa/jiagu/classify"
+)
+
+// KFold 分层k折划分, 每折中各分类的比例与全部样本一致
+func KFold(samples []classify.Sample, k int, seed int64) [][]classify.Sample {
+	if k < 2 {
+		k = 2
+	}
+	var (
+		folds  = make([][]classify.Sample, k)
+		groups = make(map[string][]classify.Sample)
+		labels []string
+		next   int
+	)
+	for _, sample := range samples {
+		if _, found := groups[sample.Label]; !found {
+			labels = append(labels, sample.Label)
+		}
+		groups[sample.Label] = append(groups[sample.Label], sample)
+	}
+	sort.Strings(labels)
+	rnd := rand.New(rand.NewSource(seed))
+	for _, label := range labels {
+		group := groups[label]
+		rnd.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		// 各分类接续上一分类的折序号轮流分配, 使各折样本数尽量均衡
+		for _, sample := range group {
+			folds[next] = append(folds[next], sample)
+			next = (next + 1) % k
+		}
+	}
+	return folds
+}
+
+// CrossValidate 分层k折交叉验证, 汇总全部折的预测结果生成报告. 样本需已分词
+func CrossValidate(samples []classify.Sample, k int, seed int64, train classify.TrainFunc) *Report {
+	folds := KFold(samples, k, seed)
+	matrix := NewConfusionMatrix()
+	accuracies := make([]float64, 0, len(folds))
+	for idx, testSet := range folds {
+		if len(testSet) == 0 {
+			continue
+		}
+		var trainSet []classify.Sample
+		for i, fold := range folds {
+			if i != idx {
+				trainSet = append(trainSet, fold...)
+			}
+		}
+		fold := confusion(train(trainSet), testSet)
+		accuracies = append(accuracies, fold.Accuracy())
+		matrix.Merge(fold)
+	}
+	report := NewReport(matrix)
+	report.Folds = accuracies
+	return report
+}
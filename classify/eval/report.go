@@ -0,0 +1,111 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/bububa/jiagu/classify"
+)
+
+// Metrics 分类评估指标
+type Metrics struct {
+	Label     string  `json:"label,omitempty"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"` // 真实分类样本数
+}
+
+// Report 评估报告
+type Report struct {
+	Classes  []Metrics        `json:"classes,omitempty"`
+	Macro    Metrics          `json:"macro"` // 各分类指标的算术平均
+	Micro    Metrics          `json:"micro"` // 全部样本汇总计算的指标
+	Accuracy float64          `json:"accuracy"`
+	Folds    []float64        `json:"folds,omitempty"` // 交叉验证各折的准确率
+	Matrix   *ConfusionMatrix `json:"-"`
+}
+
+// NewReport 根据混淆矩阵生成评估报告
+func NewReport(matrix *ConfusionMatrix) *Report {
+	report := &Report{
+		Matrix:   matrix,
+		Accuracy: matrix.Accuracy(),
+		Macro:    Metrics{Label: "macro avg", Support: matrix.Total()},
+		Micro:    Metrics{Label: "micro avg", Support: matrix.Total()},
+	}
+	labels := matrix.Labels()
+	for _, label := range labels {
+		metrics := matrix.Metrics(label)
+		report.Classes = append(report.Classes, metrics)
+		report.Macro.Precision += metrics.Precision
+		report.Macro.Recall += metrics.Recall
+		report.Macro.F1 += metrics.F1
+	}
+	if l := float64(len(labels)); l > 0 {
+		report.Macro.Precision /= l
+		report.Macro.Recall /= l
+		report.Macro.F1 /= l
+	}
+	// 单标签分类中, 预测总数与真实总数相同, micro精确率、召回率及F1均等于准确率
+	report.Micro.Precision = report.Accuracy
+	report.Micro.Recall = report.Accuracy
+	report.Micro.F1 = report.Accuracy
+	return report
+}
+
+// Evaluate 在已分词的样本上评估分类器
+func Evaluate(model classify.Classifier, samples []classify.Sample) *Report {
+	return NewReport(confusion(model, samples))
+}
+
+func confusion(model classify.Classifier, samples []classify.Sample) *ConfusionMatrix {
+	matrix := NewConfusionMatrix()
+	for _, sample := range samples {
+		predicted, _ := model.Classify(sample.Words)
+		matrix.Add(sample.Label, predicted)
+	}
+	return matrix
+}
+
+// Write 输出文本格式的评估报告
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "\tprecision\trecall\tf1\tsupport\t")
+	for _, metrics := range r.Classes {
+		writeMetrics(tw, metrics)
+	}
+	fmt.Fprintln(tw, "\t\t\t\t\t")
+	fmt.Fprintf(tw, "accuracy\t\t\t%.4f\t%d\t\n", r.Accuracy, r.Micro.Support)
+	writeMetrics(tw, r.Macro)
+	writeMetrics(tw, r.Micro)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for idx, accuracy := range r.Folds {
+		fmt.Fprintf(w, "fold %d accuracy: %.4f\n", idx+1, accuracy)
+	}
+	if r.Matrix == nil {
+		return nil
+	}
+	fmt.Fprintln(w, "\nconfusion matrix (rows: actual, columns: predicted)")
+	labels := r.Matrix.Labels()
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	for _, label := range labels {
+		fmt.Fprintf(tw, "\t%s", label)
+	}
+	fmt.Fprintln(tw, "\t")
+	for _, actual := range labels {
+		fmt.Fprint(tw, actual)
+		for _, predicted := range labels {
+			fmt.Fprintf(tw, "\t%d", r.Matrix.Count(actual, predicted))
+		}
+		fmt.Fprintln(tw, "\t")
+	}
+	return tw.Flush()
+}
+
+func writeMetrics(w io.Writer, metrics Metrics) {
+	fmt.Fprintf(w, "%s\t%.4f\t%.4f\t%.4f\t%d\t\n", metrics.Label, metrics.Precision, metrics.Recall, metrics.F1, metrics.Support)
+}
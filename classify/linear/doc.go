@@ -0,0 +1,2 @@
+// Package linear 基于稀疏特征的线性文本分类, 支持多项逻辑回归及线性SVM
+package linear
@@ -0,0 +1,158 @@
+package linear
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bububa/jiagu/classify/selection"
+)
+
+// Weighting 特征权重方式
+type Weighting = string
+
+const (
+	// Count_Weighting 词频
+	Count_Weighting Weighting = "count"
+	// Binary_Weighting 是否出现
+	Binary_Weighting Weighting = "binary"
+	// TFIDF_Weighting 词频-逆文档频率
+	TFIDF_Weighting Weighting = "tfidf"
+)
+
+// NGRAM_SEPARATOR n-gram特征中词之间的分隔符
+const NGRAM_SEPARATOR = "_"
+
+// Feature 稀疏特征
+type Feature struct {
+	Index int
+	Value float64
+}
+
+// Vector 稀疏特征向量, 按Index升序排列
+type Vector []Feature
+
+// Featurizer 将分词结果转换为稀疏特征向量
+type Featurizer struct {
+	ngram     int
+	weighting Weighting
+	vocab     map[string]int
+	terms     []string
+	df        []float64
+	docs      float64
+	allowed   selection.Vocabulary
+}
+
+// NewFeaturizer 新建Featurizer, ngram为最大n-gram长度
+func NewFeaturizer(ngram int, weighting Weighting) *Featurizer {
+	if ngram < 1 {
+		ngram = 1
+	}
+	if weighting == "" {
+		weighting = TFIDF_Weighting
+	}
+	return &Featurizer{
+		ngram:     ngram,
+		weighting: weighting,
+		vocab:     make(map[string]int),
+	}
+}
+
+// NGram 最大n-gram长度
+func (f *Featurizer) NGram() int {
+	return f.ngram
+}
+
+// Weighting 特征权重方式
+func (f *Featurizer) Weighting() Weighting {
+	return f.weighting
+}
+
+// Len 特征数量
+func (f *Featurizer) Len() int {
+	return len(f.terms)
+}
+
+// Term 特征对应的词或n-gram
+func (f *Featurizer) Term(idx int) string {
+	return f.terms[idx]
+}
+
+// Terms 生成1到ngram的全部n-gram
+func (f *Featurizer) Terms(words []string) []string {
+	ret := make([]string, 0, len(words)*f.ngram)
+	ret = append(ret, words...)
+	for n := 2; n <= f.ngram; n++ {
+		for idx := 0; idx+n <= len(words); idx++ {
+			ret = append(ret, strings.Join(words[idx:idx+n], NGRAM_SEPARATOR))
+		}
+	}
+	return ret
+}
+
+// SetVocabulary 限制Fit只使用词表中的词或n-gram, nil表示不限制
+func (f *Featurizer) SetVocabulary(vocab selection.Vocabulary) {
+	f.allowed = vocab
+}
+
+// Fit 根据训练文档建立词表及文档频率
+func (f *Featurizer) Fit(docs [][]string) {
+	for _, words := range docs {
+		seen := make(map[int]struct{})
+		for _, term := range f.Terms(words) {
+			if f.allowed != nil && !f.allowed.Contains(term) {
+				continue
+			}
+			idx, found := f.vocab[term]
+			if !found {
+				idx = len(f.terms)
+				f.vocab[term] = idx
+				f.terms = append(f.terms, term)
+				f.df = append(f.df, 0)
+			}
+			if _, found := seen[idx]; !found {
+				seen[idx] = struct{}{}
+				f.df[idx]++
+			}
+		}
+		f.docs++
+	}
+}
+
+// Transform 转换为L2归一化的稀疏特征向量, 忽略词表以外的词
+func (f *Featurizer) Transform(words []string) Vector {
+	counts := make(map[int]float64)
+	for _, term := range f.Terms(words) {
+		if idx, found := f.vocab[term]; found {
+			counts[idx]++
+		}
+	}
+	vec := make(Vector, 0, len(counts))
+	var norm float64
+	for idx, count := range counts {
+		value := count
+		switch f.weighting {
+		case Binary_Weighting:
+			value = 1
+		case TFIDF_Weighting:
+			value = count * f.idf(idx)
+		}
+		vec = append(vec, Feature{Index: idx, Value: value})
+		norm += value * value
+	}
+	sort.Slice(vec, func(i, j int) bool {
+		return vec[i].Index < vec[j].Index
+	})
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for idx := range vec {
+			vec[idx].Value /= norm
+		}
+	}
+	return vec
+}
+
+// idf 平滑的逆文档频率
+func (f *Featurizer) idf(idx int) float64 {
+	return math.Log((1+f.docs)/(1+f.df[idx])) + 1
+}
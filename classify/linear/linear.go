@@ -0,0 +1,180 @@
+package linear
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/bububa/jiagu/classify"
+)
+
+// Algorithm 线性模型训练算法
+type Algorithm = string
+
+const (
+	// Logistic_Algorithm 多项逻辑回归
+	Logistic_Algorithm Algorithm = "logistic"
+	// SVM_Algorithm 一对多线性SVM
+	SVM_Algorithm Algorithm = "svm"
+)
+
+// Linear 线性文本分类模型
+type Linear struct {
+	algorithm  Algorithm
+	featurizer *Featurizer
+	categories []string
+	weights    [][]float64
+	bias       []float64
+}
+
+// New 新建Linear
+func New(algorithm Algorithm, featurizer *Featurizer) *Linear {
+	if algorithm == "" {
+		algorithm = Logistic_Algorithm
+	}
+	return &Linear{
+		algorithm:  algorithm,
+		featurizer: featurizer,
+	}
+}
+
+// NewFromModel 从model新建Linear
+func NewFromModel(model Model) *Linear {
+	featurizer := NewFeaturizer(model.NGram, model.Weighting)
+	featurizer.terms = model.Terms
+	featurizer.df = model.DF
+	featurizer.docs = model.Docs
+	for idx, term := range model.Terms {
+		featurizer.vocab[term] = idx
+	}
+	l := New(model.Algorithm, featurizer)
+	l.categories = model.Categories
+	l.weights = model.Weights
+	l.bias = model.Bias
+	return l
+}
+
+// NewFromReader 从io.Reader新建Linear
+func NewFromReader(r io.Reader) (*Linear, error) {
+	var model Model
+	if err := gob.NewDecoder(r).Decode(&model); err != nil {
+		return nil, err
+	}
+	return NewFromModel(model), nil
+}
+
+// NewFromGzipFile 从SaveGzipFile保存的gzip压缩模型文件新建Linear
+func NewFromGzipFile(loc string) (*Linear, error) {
+	fd, err := os.Open(loc)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	gr, err := gzip.NewReader(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return NewFromReader(gr)
+}
+
+// Save save model
+func (l *Linear) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(l.ToModel())
+}
+
+// SaveGzipFile save gzip compressed model to a file
+func (l *Linear) SaveGzipFile(loc string) error {
+	fd, err := os.OpenFile(loc, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	gw := gzip.NewWriter(fd)
+	if err := l.Save(gw); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+// ToModel 转换为模型
+func (l *Linear) ToModel() Model {
+	return Model{
+		Algorithm:  l.algorithm,
+		Categories: l.categories,
+		Weights:    l.weights,
+		Bias:       l.bias,
+		NGram:      l.featurizer.ngram,
+		Weighting:  l.featurizer.weighting,
+		Terms:      l.featurizer.terms,
+		DF:         l.featurizer.df,
+		Docs:       l.featurizer.docs,
+	}
+}
+
+// Algorithm 训练算法
+func (l *Linear) Algorithm() Algorithm {
+	return l.algorithm
+}
+
+// Featurizer 特征转换器
+func (l *Linear) Featurizer() *Featurizer {
+	return l.featurizer
+}
+
+// Categories 全部分类
+func (l *Linear) Categories() []string {
+	return l.categories
+}
+
+// Classify 分类, 返回概率最高的分类及其概率
+func (l *Linear) Classify(words []string) (string, float64) {
+	probs := l.ClassifyProba(words)
+	if len(probs) == 0 {
+		return "", 0
+	}
+	return probs[0].Category, probs[0].Value
+}
+
+// ClassifyProba 计算全部分类的概率, 按概率降序排列. SVM的概率为决策值的softmax归一化, 仅用于排序及比较
+func (l *Linear) ClassifyProba(words []string) []classify.Prob {
+	scores := l.Scores(l.featurizer.Transform(words))
+	if len(scores) == 0 {
+		return nil
+	}
+	maxScore := math.Inf(-1)
+	for _, score := range scores {
+		if score > maxScore {
+			maxScore = score
+		}
+	}
+	var sum float64
+	probs := make([]classify.Prob, len(scores))
+	for idx, score := range scores {
+		value := math.Exp(score - maxScore)
+		probs[idx] = classify.Prob{Category: l.categories[idx], Value: value}
+		sum += value
+	}
+	for idx := range probs {
+		probs[idx].Value /= sum
+	}
+	sort.Sort(sort.Reverse(classify.ProbSlice(probs)))
+	return probs
+}
+
+// Scores 计算特征向量在各分类上的决策值
+func (l *Linear) Scores(vec Vector) []float64 {
+	scores := make([]float64, len(l.categories))
+	for k := range l.categories {
+		score := l.bias[k]
+		for _, f := range vec {
+			score += l.weights[k][f.Index] * f.Value
+		}
+		scores[k] = score
+	}
+	return scores
+}
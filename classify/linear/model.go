@@ -0,0 +1,14 @@
+package linear
+
+// Model linear model for saving
+type Model struct {
+	Algorithm  Algorithm   `json:"algorithm,omitempty"`
+	Categories []string    `json:"categories,omitempty"`
+	Weights    [][]float64 `json:"weights,omitempty"`
+	Bias       []float64   `json:"bias,omitempty"`
+	NGram      int         `json:"ngram,omitempty"`
+	Weighting  Weighting   `json:"weighting,omitempty"`
+	Terms      []string    `json:"terms,omitempty"`
+	DF         []float64   `json:"df,omitempty"`
+	Docs       float64     `json:"docs,omitempty"`
+}
@@ -0,0 +1,263 @@
+package linear
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/bububa/jiagu/classify"
+	"github.com/bububa/jiagu/classify/selection"
+	"github.com/bububa/jiagu/segment"
+	"github.com/bububa/jiagu/stopwords"
+)
+
+const (
+	// DEFAULT_EPOCHS 默认训练轮数
+	DEFAULT_EPOCHS int = 10
+	// DEFAULT_LEARNING_RATE 逻辑回归默认初始学习率
+	DEFAULT_LEARNING_RATE float64 = 0.5
+	// DEFAULT_LAMBDA 默认L2正则化系数
+	DEFAULT_LAMBDA float64 = 1e-4
+)
+
+// Trainer 从标注文本训练线性模型
+type Trainer struct {
+	tokenizer classify.Tokenizer
+	algorithm Algorithm
+	ngram     int
+	weighting Weighting
+	epochs    int
+	rate      float64
+	lambda    float64
+	seed      int64
+	selection selection.Method
+	topK      int
+	minDF     int
+}
+
+// NewTrainer 新建Trainer
+func NewTrainer(seg *segment.Segment, stwords *stopwords.Stopwords) *Trainer {
+	return &Trainer{
+		tokenizer: classify.NewTokenizer(seg, stwords),
+		algorithm: Logistic_Algorithm,
+		ngram:     1,
+		weighting: TFIDF_Weighting,
+		epochs:    DEFAULT_EPOCHS,
+		rate:      DEFAULT_LEARNING_RATE,
+		lambda:    DEFAULT_LAMBDA,
+		seed:      1,
+	}
+}
+
+// SetAlgorithm 设置训练算法
+func (t *Trainer) SetAlgorithm(algorithm Algorithm) {
+	t.algorithm = algorithm
+}
+
+// SetNGram 设置最大n-gram长度
+func (t *Trainer) SetNGram(ngram int) {
+	t.ngram = ngram
+}
+
+// SetWeighting 设置特征权重方式
+func (t *Trainer) SetWeighting(weighting Weighting) {
+	t.weighting = weighting
+}
+
+// SetEpochs 设置训练轮数
+func (t *Trainer) SetEpochs(epochs int) {
+	t.epochs = epochs
+}
+
+// SetLearningRate 设置逻辑回归初始学习率, SVM使用Pegasos步长
+func (t *Trainer) SetLearningRate(rate float64) {
+	t.rate = rate
+}
+
+// SetLambda 设置L2正则化系数
+func (t *Trainer) SetLambda(lambda float64) {
+	t.lambda = lambda
+}
+
+// SetSeed 设置打乱样本顺序的随机种子
+func (t *Trainer) SetSeed(seed int64) {
+	t.seed = seed
+}
+
+// SetSelection 设置特征选择方法, 仅使用得分最高的topK个词或n-gram训练, 忽略文档频率小于minDF的特征
+func (t *Trainer) SetSelection(method selection.Method, topK int, minDF int) {
+	t.selection = method
+	t.topK = topK
+	t.minDF = minDF
+}
+
+// Tokenize 分词并去除停用词
+func (t *Trainer) Tokenize(txt string) []string {
+	return t.tokenizer(txt)
+}
+
+// Train 训练模型, 未分词的样本使用Tokenize分词
+func (t *Trainer) Train(samples []classify.Sample) *Linear {
+	classify.Tokenize(samples, t.Tokenize)
+	featurizer := NewFeaturizer(t.ngram, t.weighting)
+	docs := make([][]string, len(samples))
+	for idx, sample := range samples {
+		docs[idx] = sample.Words
+	}
+	if t.selection != "" {
+		stats := selection.NewStats()
+		for _, sample := range samples {
+			stats.Add(sample.Label, featurizer.Terms(sample.Words))
+		}
+		featurizer.SetVocabulary(selection.NewVocabulary(stats.Rank(t.selection, t.minDF), t.topK))
+	}
+	featurizer.Fit(docs)
+	model := New(t.algorithm, featurizer)
+	categories := make(map[string]int)
+	for _, sample := range samples {
+		if _, found := categories[sample.Label]; !found {
+			categories[sample.Label] = 0
+			model.categories = append(model.categories, sample.Label)
+		}
+	}
+	sort.Strings(model.categories)
+	for idx, cat := range model.categories {
+		categories[cat] = idx
+	}
+	vecs := make([]Vector, len(samples))
+	labels := make([]int, len(samples))
+	for idx, sample := range samples {
+		vecs[idx] = featurizer.Transform(sample.Words)
+		labels[idx] = categories[sample.Label]
+	}
+	ws := make([]*weights, len(model.categories))
+	for k := range ws {
+		ws[k] = newWeights(featurizer.Len())
+	}
+	rnd := rand.New(rand.NewSource(t.seed))
+	order := rnd.Perm(len(samples))
+	var step int
+	for epoch := 0; epoch < t.epochs; epoch++ {
+		rnd.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+		for _, idx := range order {
+			step++
+			if t.algorithm == SVM_Algorithm {
+				t.pegasosStep(ws, vecs[idx], labels[idx], step)
+			} else {
+				t.logisticStep(ws, vecs[idx], labels[idx], step)
+			}
+		}
+	}
+	model.weights = make([][]float64, len(ws))
+	model.bias = make([]float64, len(ws))
+	for k, w := range ws {
+		model.weights[k], model.bias[k] = w.dense()
+	}
+	return model
+}
+
+// logisticStep 多项逻辑回归随机梯度下降
+func (t *Trainer) logisticStep(ws []*weights, vec Vector, label int, step int) {
+	eta := t.rate / (1 + t.rate*t.lambda*float64(step))
+	scores := make([]float64, len(ws))
+	maxScore := math.Inf(-1)
+	for k, w := range ws {
+		scores[k] = w.dot(vec)
+		if scores[k] > maxScore {
+			maxScore = scores[k]
+		}
+	}
+	var sum float64
+	for k := range scores {
+		scores[k] = math.Exp(scores[k] - maxScore)
+		sum += scores[k]
+	}
+	for k, w := range ws {
+		grad := scores[k] / sum
+		if k == label {
+			grad--
+		}
+		w.decay(1 - eta*t.lambda)
+		w.add(vec, -eta*grad)
+	}
+}
+
+// pegasosStep 一对多线性SVM的Pegasos更新
+func (t *Trainer) pegasosStep(ws []*weights, vec Vector, label int, step int) {
+	eta := 1 / (t.lambda * float64(step))
+	for k, w := range ws {
+		y := -1.0
+		if k == label {
+			y = 1
+		}
+		margin := y * w.dot(vec)
+		w.decay(1 - eta*t.lambda)
+		if margin < 1 {
+			w.add(vec, eta*y)
+		}
+	}
+}
+
+// Eval 计算模型在样本上的准确率
+func (t *Trainer) Eval(model *Linear, samples []classify.Sample) float64 {
+	return classify.Eval(model, samples, t.tokenizer)
+}
+
+// weights 训练中的权重向量, 实际权重为values*scale, L2正则化衰减时只需更新scale
+type weights struct {
+	values []float64
+	bias   float64
+	scale  float64
+}
+
+func newWeights(size int) *weights {
+	return &weights{
+		values: make([]float64, size),
+		scale:  1,
+	}
+}
+
+func (w *weights) dot(vec Vector) float64 {
+	score := w.bias
+	for _, f := range vec {
+		score += w.values[f.Index] * f.Value
+	}
+	return score * w.scale
+}
+
+func (w *weights) add(vec Vector, coef float64) {
+	coef /= w.scale
+	for _, f := range vec {
+		w.values[f.Index] += coef * f.Value
+	}
+	w.bias += coef
+}
+
+func (w *weights) decay(factor float64) {
+	if factor <= 0 {
+		for idx := range w.values {
+			w.values[idx] = 0
+		}
+		w.bias = 0
+		w.scale = 1
+		return
+	}
+	w.scale *= factor
+	if w.scale < 1e-9 {
+		for idx := range w.values {
+			w.values[idx] *= w.scale
+		}
+		w.bias *= w.scale
+		w.scale = 1
+	}
+}
+
+func (w *weights) dense() ([]float64, float64) {
+	ret := make([]float64, len(w.values))
+	for idx, v := range w.values {
+		ret[idx] = v * w.scale
+	}
+	return ret, w.bias * w.scale
+}
@@ -0,0 +1,24 @@
+package classify
+
+// Prob 分类概率
+type Prob struct {
+	Category string  `json:"category,omitempty"`
+	Value    float64 `json:"value"`
+}
+
+// ProbSlice Prob array
+type ProbSlice []Prob
+
+// Len implement sort.Sorter
+func (s ProbSlice) Len() int { return len(s) }
+
+// Swap implement sort.Sorter
+func (s ProbSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// Less implement sort.Sorter
+func (s ProbSlice) Less(i, j int) bool {
+	if s[i].Value == s[j].Value {
+		return s[i].Category > s[j].Category
+	}
+	return s[i].Value < s[j].Value
+}
@@ -0,0 +1,98 @@
+package classify
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Sample 标注样本
+type Sample struct {
+	Label string   `json:"label,omitempty"`
+	Text  string   `json:"text,omitempty"`
+	Words []string `json:"words,omitempty"`
+}
+
+// Tokenizer 分词方法
+type Tokenizer = func(string) []string
+
+// LoadTSV 加载TSV格式样本, 每行格式: 标签\t文本
+func LoadTSV(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	buf := bufio.NewReader(r)
+	for {
+		line, err := buf.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if fields := strings.SplitN(strings.TrimSpace(line), "\t", 2); len(fields) == 2 {
+			label, text := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+			if label != "" && text != "" {
+				samples = append(samples, Sample{Label: label, Text: text})
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return samples, nil
+}
+
+// LoadJSONL 加载JSONL格式样本, 每行格式: {"label": "positive", "text": "..."}
+func LoadJSONL(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	decoder := json.NewDecoder(r)
+	for {
+		var sample Sample
+		if err := decoder.Decode(&sample); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if sample.Label == "" || (sample.Text == "" && len(sample.Words) == 0) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// Tokenize 对未分词的样本分词
+func Tokenize(samples []Sample, tokenizer Tokenizer) {
+	for idx := range samples {
+		if len(samples[idx].Words) == 0 {
+			samples[idx].Words = tokenizer(samples[idx].Text)
+		}
+	}
+}
+
+// Split 按分类分层随机拆分训练集及测试集, ratio为测试集比例
+func Split(samples []Sample, ratio float64, seed int64) ([]Sample, []Sample) {
+	var (
+		train  []Sample
+		test   []Sample
+		groups = make(map[string][]Sample)
+		labels []string
+	)
+	for _, sample := range samples {
+		if _, found := groups[sample.Label]; !found {
+			labels = append(labels, sample.Label)
+		}
+		groups[sample.Label] = append(groups[sample.Label], sample)
+	}
+	sort.Strings(labels)
+	rnd := rand.New(rand.NewSource(seed))
+	for _, label := range labels {
+		group := groups[label]
+		rnd.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		n := int(float64(len(group))*ratio + 0.5)
+		test = append(test, group[:n]...)
+		train = append(train, group[n:]...)
+	}
+	return train, test
+}
@@ -0,0 +1,2 @@
+// Package selection 文本分类特征选择, 包含卡方检验、信息增益及文档频率过滤
+package selection
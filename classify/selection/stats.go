@@ -0,0 +1,212 @@
+package selection
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bububa/jiagu/classify"
+)
+
+// Method 特征选择方法
+type Method = string
+
+const (
+	// Chi2_Method 卡方检验, 词的得分为各分类卡方值的最大值
+	Chi2_Method Method = "chi2"
+	// InfoGain_Method 信息增益
+	InfoGain_Method Method = "ig"
+	// DF_Method 文档频率
+	DF_Method Method = "df"
+)
+
+// Score 词的特征选择得分
+type Score struct {
+	Term  string  `json:"term,omitempty"`
+	Value float64 `json:"value"`
+	DF    int     `json:"df"` // 文档频率
+}
+
+// Stats 词与分类的文档频率统计
+type Stats struct {
+	categories []string
+	catDocs    map[string]float64
+	termDocs   map[string]map[string]float64 // 词 -> 分类 -> 包含该词的文档数
+	docs       float64
+}
+
+// NewStats 新建Stats
+func NewStats() *Stats {
+	return &Stats{
+		catDocs:  make(map[string]float64),
+		termDocs: make(map[string]map[string]float64),
+	}
+}
+
+// Add 添加一篇文档, 同一文档中重复的词只计一次
+func (s *Stats) Add(label string, terms []string) {
+	if _, found := s.catDocs[label]; !found {
+		s.categories = append(s.categories, label)
+		sort.Strings(s.categories)
+	}
+	s.catDocs[label]++
+	s.docs++
+	seen := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		if _, found := seen[term]; found {
+			continue
+		}
+		seen[term] = struct{}{}
+		cats, found := s.termDocs[term]
+		if !found {
+			cats = make(map[string]float64)
+			s.termDocs[term] = cats
+		}
+		cats[label]++
+	}
+}
+
+// AddSamples 添加已分词的样本
+func (s *Stats) AddSamples(samples []classify.Sample) {
+	for _, sample := range samples {
+		s.Add(sample.Label, sample.Words)
+	}
+}
+
+// Categories 全部分类
+func (s *Stats) Categories() []string {
+	return s.categories
+}
+
+// Len 词表大小
+func (s *Stats) Len() int {
+	return len(s.termDocs)
+}
+
+// DF 包含该词的文档数
+func (s *Stats) DF(term string) int {
+	var df float64
+	for _, docs := range s.termDocs[term] {
+		df += docs
+	}
+	return int(df)
+}
+
+// Chi2 词与分类的卡方值
+func (s *Stats) Chi2(term string, cat string) float64 {
+	a, b, c, d := s.contingency(term, cat)
+	denominator := (a + c) * (b + d) * (a + b) * (c + d)
+	if denominator == 0 {
+		return 0
+	}
+	return s.docs * (a*d - b*c) * (a*d - b*c) / denominator
+}
+
+// InfoGain 词对全部分类的信息增益
+func (s *Stats) InfoGain(term string) float64 {
+	df := float64(s.DF(term))
+	if s.docs == 0 {
+		return 0
+	}
+	var (
+		with    = make([]float64, 0, len(s.categories))
+		without = make([]float64, 0, len(s.categories))
+		prior   = make([]float64, 0, len(s.categories))
+	)
+	for _, cat := range s.categories {
+		docs := s.termDocs[term][cat]
+		with = append(with, docs)
+		without = append(without, s.catDocs[cat]-docs)
+		prior = append(prior, s.catDocs[cat])
+	}
+	return entropy(prior, s.docs) - df/s.docs*entropy(with, df) - (s.docs-df)/s.docs*entropy(without, s.docs-df)
+}
+
+// categoryInfoGain 词对分类与其他分类二分的信息增益
+func (s *Stats) categoryInfoGain(term string, cat string) float64 {
+	a, b, c, d := s.contingency(term, cat)
+	if s.docs == 0 {
+		return 0
+	}
+	return entropy([]float64{a + c, b + d}, s.docs) - (a+b)/s.docs*entropy([]float64{a, b}, a+b) - (c+d)/s.docs*entropy([]float64{c, d}, c+d)
+}
+
+// contingency 2x2列联表: a含词且属于分类, b含词不属于分类, c不含词属于分类, d不含词不属于分类
+func (s *Stats) contingency(term string, cat string) (float64, float64, float64, float64) {
+	a := s.termDocs[term][cat]
+	b := float64(s.DF(term)) - a
+	c := s.catDocs[cat] - a
+	d := s.docs - a - b - c
+	return a, b, c, d
+}
+
+// Score 词的特征选择得分
+func (s *Stats) Score(method Method, term string) float64 {
+	switch method {
+	case Chi2_Method:
+		var score float64
+		for _, cat := range s.categories {
+			score = math.Max(score, s.Chi2(term, cat))
+		}
+		return score
+	case InfoGain_Method:
+		return s.InfoGain(term)
+	}
+	return float64(s.DF(term))
+}
+
+// CategoryScore 词在分类上的特征选择得分
+func (s *Stats) CategoryScore(method Method, term string, cat string) float64 {
+	switch method {
+	case Chi2_Method:
+		return s.Chi2(term, cat)
+	case InfoGain_Method:
+		return s.categoryInfoGain(term, cat)
+	}
+	return s.termDocs[term][cat]
+}
+
+// Rank 按得分降序排列词表, 忽略文档频率小于minDF的词
+func (s *Stats) Rank(method Method, minDF int) []Score {
+	return s.rank(minDF, func(term string) float64 {
+		return s.Score(method, term)
+	})
+}
+
+// RankCategory 按在分类上的得分降序排列词表, 忽略文档频率小于minDF的词
+func (s *Stats) RankCategory(method Method, cat string, minDF int) []Score {
+	return s.rank(minDF, func(term string) float64 {
+		return s.CategoryScore(method, term, cat)
+	})
+}
+
+func (s *Stats) rank(minDF int, scorer func(string) float64) []Score {
+	ret := make([]Score, 0, len(s.termDocs))
+	for term := range s.termDocs {
+		df := s.DF(term)
+		if df < minDF {
+			continue
+		}
+		ret = append(ret, Score{Term: term, Value: scorer(term), DF: df})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Value == ret[j].Value {
+			return ret[i].Term < ret[j].Term
+		}
+		return ret[i].Value > ret[j].Value
+	})
+	return ret
+}
+
+func entropy(counts []float64, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	var ret float64
+	for _, count := range counts {
+		if count > 0 {
+			p := count / total
+			ret -= p * math.Log(p)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,42 @@
+package selection
+
+import "github.com/bububa/jiagu/classify"
+
+// Vocabulary 选中的特征词表
+type Vocabulary map[string]struct{}
+
+// NewVocabulary 取得分最高的k个词, k <= 0时保留全部
+func NewVocabulary(scores []Score, k int) Vocabulary {
+	if k > 0 && len(scores) > k {
+		scores = scores[:k]
+	}
+	vocab := make(Vocabulary, len(scores))
+	for _, score := range scores {
+		vocab[score.Term] = struct{}{}
+	}
+	return vocab
+}
+
+// Select 根据已分词的样本选择特征词表
+func Select(samples []classify.Sample, method Method, k int, minDF int) Vocabulary {
+	stats := NewStats()
+	stats.AddSamples(samples)
+	return NewVocabulary(stats.Rank(method, minDF), k)
+}
+
+// Contains 判断词是否在词表中
+func (v Vocabulary) Contains(term string) bool {
+	_, found := v[term]
+	return found
+}
+
+// Filter 过滤词表以外的词
+func (v Vocabulary) Filter(terms []string) []string {
+	ret := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if v.Contains(term) {
+			ret = append(ret, term)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,24 @@
+package classify
+
+import (
+	"strings"
+
+	"github.com/bububa/jiagu/segment"
+	"github.com/bububa/jiagu/stopwords"
+)
+
+// NewTokenizer 新建分词方法, 分词并去除停用词, stwords为空时不去除停用词
+func NewTokenizer(seg *segment.Segment, stwords *stopwords.Stopwords) Tokenizer {
+	return func(txt string) []string {
+		words := seg.Seg(txt, segment.Default_SegMode)
+		ret := make([]string, 0, len(words))
+		for _, w := range words {
+			w = strings.TrimSpace(w)
+			if w == "" || (stwords != nil && stwords.Exists(w)) {
+				continue
+			}
+			ret = append(ret, w)
+		}
+		return ret
+	}
+}
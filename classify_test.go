@@ -0,0 +1,209 @@
+package jiagu
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bububa/jiagu/classify"
+	"github.com/bububa/jiagu/classify/bayes"
+	"github.com/bububa/jiagu/classify/eval"
+	"github.com/bububa/jiagu/classify/linear"
+	"github.com/bububa/jiagu/classify/selection"
+)
+
+// TestLinear 测试逻辑回归及线性SVM文本分类
+func TestLinear(t *testing.T) {
+	data := "体育\t球队 比赛 进球 冠军\n体育\t比赛 球员 教练\n体育\t冠军 球员 进球\n" +
+		"财经\t股票 市场 上涨\n财经\t银行 利率 股票\n财经\t市场 投资 利率\n" +
+		"科技\t手机 芯片 发布\n科技\t芯片 人工智能 算法\n科技\t手机 算法 发布\n"
+	for _, algorithm := range []linear.Algorithm{linear.Logistic_Algorithm, linear.SVM_Algorithm} {
+		samples, err := classify.LoadTSV(strings.NewReader(data))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		classify.Tokenize(samples, strings.Fields)
+		trainer := linear.NewTrainer(nil, nil)
+		trainer.SetAlgorithm(algorithm)
+		trainer.SetNGram(2)
+		trainer.SetEpochs(20)
+		model := trainer.Train(samples)
+		var buf bytes.Buffer
+		if err := model.Save(&buf); err != nil {
+			t.Error(err)
+			return
+		}
+		loaded, err := linear.NewFromReader(&buf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		tests := [][]string{
+			{"球员", "进球"},
+			{"股票", "利率"},
+			{"芯片", "发布"},
+		}
+		exps := []string{"体育", "财经", "科技"}
+		for idx, words := range tests {
+			if cat, prob := loaded.Classify(words); cat != exps[idx] {
+				t.Errorf("algorithm:%s, expects:%s, result:%s, prob:%f", algorithm, exps[idx], cat, prob)
+			}
+			var sum float64
+			for _, p := range loaded.ClassifyProba(words) {
+				sum += p.Value
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("algorithm:%s, expects sum:1, result:%f", algorithm, sum)
+			}
+		}
+		if accuracy := trainer.Eval(loaded, samples); accuracy != 1 {
+			t.Errorf("algorithm:%s, expects accuracy:1, result:%f", algorithm, accuracy)
+		}
+		loc := filepath.Join(t.TempDir(), "classify.model")
+		if err := model.SaveGzipFile(loc); err != nil {
+			t.Error(err)
+			return
+		}
+		gzipped, err := linear.NewFromGzipFile(loc)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if accuracy := classify.Eval(gzipped, samples, strings.Fields); accuracy != 1 {
+			t.Errorf("algorithm:%s, expects gzip model accuracy:1, result:%f", algorithm, accuracy)
+		}
+	}
+}
+
+// TestEval 测试混淆矩阵及分层k折交叉验证
+func TestEval(t *testing.T) {
+	matrix := eval.NewConfusionMatrix()
+	pairs := [][2]string{
+		{"a", "a"}, {"a", "a"}, {"a", "b"},
+		{"b", "b"}, {"b", "a"},
+		{"c", "c"},
+	}
+	for _, pair := range pairs {
+		matrix.Add(pair[0], pair[1])
+	}
+	report := eval.NewReport(matrix)
+	if math.Abs(report.Accuracy-4.0/6) > 1e-9 || report.Micro.F1 != report.Accuracy {
+		t.Errorf("result: %+v", report)
+	}
+	// a: precision 2/3, recall 2/3; b: precision 1/2, recall 1/2; c: 1, 1
+	if exp := (2.0/3 + 0.5 + 1) / 3; math.Abs(report.Macro.Precision-exp) > 1e-9 || math.Abs(report.Macro.F1-exp) > 1e-9 {
+		t.Errorf("expects macro:%f, result: %+v", exp, report.Macro)
+	}
+	if matrix.Count("a", "b") != 1 || report.Classes[0].Support != 3 {
+		t.Errorf("result: %+v", report.Classes)
+	}
+	var buf bytes.Buffer
+	if err := report.Write(&buf); err != nil {
+		t.Error(err)
+	}
+
+	var samples []classify.Sample
+	for i := 0; i < 10; i++ {
+		samples = append(samples, classify.Sample{Label: "positive", Words: []string{"喜欢", "开心"}})
+		samples = append(samples, classify.Sample{Label: "negative", Words: []string{"讨厌", "失望"}})
+	}
+	folds := eval.KFold(samples, 5, 1)
+	for _, fold := range folds {
+		var positive int
+		for _, sample := range fold {
+			if sample.Label == "positive" {
+				positive++
+			}
+		}
+		if len(fold) != 4 || positive != 2 {
+			t.Errorf("unbalanced fold: %+v", fold)
+		}
+	}
+	trainer := bayes.NewTrainer(nil, nil)
+	report = eval.CrossValidate(samples, 5, 1, func(samples []classify.Sample) classify.Classifier {
+		return trainer.Train(samples)
+	})
+	if len(report.Folds) != 5 || report.Accuracy != 1 || report.Matrix.Total() != len(samples) {
+		t.Errorf("result: %+v", report)
+	}
+}
+
+// TestSelection 测试卡方检验、信息增益及文档频率特征选择
+func TestSelection(t *testing.T) {
+	samples := []classify.Sample{
+		{Label: "positive", Words: []string{"喜欢", "今天", "电影"}},
+		{Label: "positive", Words: []string{"喜欢", "开心", "电影"}},
+		{Label: "negative", Words: []string{"讨厌", "今天", "电影"}},
+		{Label: "negative", Words: []string{"讨厌", "失望", "电影"}},
+	}
+	stats := selection.NewStats()
+	stats.AddSamples(samples)
+	for _, method := range []selection.Method{selection.Chi2_Method, selection.InfoGain_Method} {
+		scores := stats.Rank(method, 0)
+		if len(scores) != stats.Len() {
+			t.Errorf("method:%s, result: %+v", method, scores)
+			continue
+		}
+		// 喜欢/讨厌完全区分分类, 电影/今天与分类无关
+		top := map[string]struct{}{scores[0].Term: {}, scores[1].Term: {}}
+		if _, found := top["喜欢"]; !found {
+			t.Errorf("method:%s, result: %+v", method, scores)
+		}
+		if _, found := top["讨厌"]; !found {
+			t.Errorf("method:%s, result: %+v", method, scores)
+		}
+		if score := stats.Score(method, "电影"); score != 0 {
+			t.Errorf("method:%s, expects 电影:0, result:%f", method, score)
+		}
+	}
+	if math.Abs(stats.Chi2("喜欢", "positive")-4) > 1e-9 || math.Abs(stats.InfoGain("喜欢")-math.Log(2)) > 1e-9 {
+		t.Errorf("chi2:%f, ig:%f", stats.Chi2("喜欢", "positive"), stats.InfoGain("喜欢"))
+	}
+	if scores := stats.Rank(selection.DF_Method, 2); len(scores) != 4 || scores[0].Term != "电影" || scores[0].DF != 4 {
+		t.Errorf("result: %+v", scores)
+	}
+	vocab := selection.Select(samples, selection.Chi2_Method, 2, 0)
+	if words := vocab.Filter([]string{"喜欢", "电影", "讨厌"}); len(words) != 2 {
+		t.Errorf("result: %v", words)
+	}
+
+	trainer := bayes.NewTrainer(nil, nil)
+	trainer.SetSelection(selection.Chi2_Method, 2, 0)
+	model := trainer.Train(samples)
+	if probe := model.GetCategory("positive"); probe.Exists("电影") || !probe.Exists("喜欢") {
+		t.Errorf("unexpected vocabulary: %+v", probe)
+	}
+	// 特征选择去除的词不影响分类结果, 保存后重新加载同样生效
+	model = trainer.Train(append([]classify.Sample{
+		{Label: "negative", Words: []string{"讨厌", "失望", "糟糕", "难过", "无聊", "电影"}},
+	}, samples...))
+	var buf bytes.Buffer
+	if err := model.Save(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	loaded, err := bayes.NewFromReader(&buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, m := range []*bayes.Bayes{model, loaded} {
+		expects := m.ClassifyProba([]string{"讨厌"})
+		probs := m.ClassifyProba([]string{"讨厌", "开心", "开心", "未知"})
+		for idx, p := range probs {
+			if p.Category != expects[idx].Category || math.Abs(p.Value-expects[idx].Value) > 1e-9 {
+				t.Errorf("result: %+v, expect: %+v", probs, expects)
+				break
+			}
+		}
+	}
+	linearTrainer := linear.NewTrainer(nil, nil)
+	linearTrainer.SetNGram(2)
+	linearTrainer.SetSelection(selection.Chi2_Method, 2, 0)
+	if l := linearTrainer.Train(samples).Featurizer().Len(); l != 2 {
+		t.Errorf("expects 2 features, result:%d", l)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bububa/jiagu"
+	"github.com/bububa/jiagu/classify"
+	"github.com/bububa/jiagu/classify/eval"
+	"github.com/bububa/jiagu/classify/linear"
+)
+
+func main() {
+	var (
+		trainPath string
+		format    string
+		modelPath string
+		testRatio float64
+		seed      int64
+		kfold     int
+		method    string
+		topK      int
+		minDF     int
+		algorithm string
+		ngram     int
+		weighting string
+		epochs    int
+		rate      float64
+		lambda    float64
+	)
+	flag.StringVar(&trainPath, "train", "", "labeled data file")
+	flag.StringVar(&format, "format", "", "data format: tsv or jsonl, detected by file extension if empty")
+	flag.StringVar(&modelPath, "model", "classify.model", "output model file")
+	flag.Float64Var(&testRatio, "test", 0.2, "held-out ratio for evaluation")
+	flag.IntVar(&kfold, "kfold", 0, "run stratified k-fold cross-validation instead of training when k > 1")
+	flag.Int64Var(&seed, "seed", 1, "random seed for splitting data and shuffling samples")
+	flag.StringVar(&algorithm, "algorithm", linear.Logistic_Algorithm, "training algorithm: logistic or svm")
+	flag.IntVar(&ngram, "ngram", 1, "max n-gram length of features")
+	flag.StringVar(&weighting, "weighting", linear.TFIDF_Weighting, "feature weighting: count, binary or tfidf")
+	flag.IntVar(&epochs, "epochs", linear.DEFAULT_EPOCHS, "training epochs")
+	flag.Float64Var(&rate, "rate", linear.DEFAULT_LEARNING_RATE, "initial learning rate for logistic regression")
+	flag.Float64Var(&lambda, "lambda", linear.DEFAULT_LAMBDA, "L2 regularization")
+	flag.StringVar(&method, "select", "", "feature selection: chi2, ig or df, disabled if empty")
+	flag.IntVar(&topK, "topk", 0, "number of selected features, 0 for all")
+	flag.IntVar(&minDF, "mindf", 0, "ignore features with document frequency lower than mindf")
+	flag.Parse()
+	if trainPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	trainPath, err := filepath.Abs(trainPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if modelPath, err = filepath.Abs(modelPath); err != nil {
+		log.Fatalln(err)
+	}
+	samples, err := loadSamples(trainPath, format)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	trainer := linear.NewTrainer(jiagu.Segment(), jiagu.Stopwords())
+	trainer.SetAlgorithm(algorithm)
+	trainer.SetNGram(ngram)
+	trainer.SetWeighting(weighting)
+	trainer.SetEpochs(epochs)
+	trainer.SetLearningRate(rate)
+	trainer.SetLambda(lambda)
+	trainer.SetSeed(seed)
+	trainer.SetSelection(method, topK, minDF)
+	classify.Tokenize(samples, trainer.Tokenize)
+	if kfold > 1 {
+		log.Printf("cross-validation: %d samples, %d folds\n", len(samples), kfold)
+		report := eval.CrossValidate(samples, kfold, seed, func(samples []classify.Sample) classify.Classifier {
+			return trainer.Train(samples)
+		})
+		if err := report.Write(os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	trainSet, testSet := classify.Split(samples, testRatio, seed)
+	log.Printf("training: %d samples, testing: %d samples\n", len(trainSet), len(testSet))
+	model := trainer.Train(trainSet)
+	log.Printf("features: %d, categories: %d\n", model.Featurizer().Len(), len(model.Categories()))
+	if len(testSet) > 0 {
+		if err := eval.Evaluate(model, testSet).Write(os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if err := model.SaveGzipFile(modelPath); err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("saved: %s\n", modelPath)
+}
+
+func loadSamples(loc string, format string) ([]classify.Sample, error) {
+	fd, err := os.Open(loc)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(loc), ".")
+	}
+	if format == "jsonl" || format == "json" {
+		return classify.LoadJSONL(fd)
+	}
+	return classify.LoadTSV(fd)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bububa/jiagu"
+	"github.com/bububa/jiagu/tfidf"
+)
+
+func main() {
+	var (
+		corpusPath string
+		outputPath string
+		minDF      int
+	)
+	flag.StringVar(&corpusPath, "corpus", "", "corpus file, one document per line")
+	flag.StringVar(&outputPath, "output", "idf.txt", "output idf table")
+	flag.IntVar(&minDF, "mindf", 1, "ignore words with document frequency lower than mindf")
+	flag.Parse()
+	if corpusPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	corpusPath, err := filepath.Abs(corpusPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if outputPath, err = filepath.Abs(outputPath); err != nil {
+		log.Fatalln(err)
+	}
+	fd, err := os.Open(corpusPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer fd.Close()
+	stwords := jiagu.Stopwords()
+	builder := tfidf.NewBuilder()
+	buf := bufio.NewReader(fd)
+	for {
+		line, err := buf.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Fatalln(err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			words := jiagu.Seg(line)
+			filtered := words[:0]
+			for _, w := range words {
+				if w = strings.TrimSpace(w); w != "" && !stwords.Exists(w) {
+					filtered = append(filtered, w)
+				}
+			}
+			builder.Add(filtered)
+		}
+		if err != nil {
+			break
+		}
+	}
+	idf := builder.Build(minDF)
+	if err := idf.SaveFile(outputPath); err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("documents: %d, words: %d, saved: %s\n", builder.Docs(), idf.Len(), outputPath)
+}
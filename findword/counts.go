@@ -0,0 +1,147 @@
+package findword
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/bububa/jiagu/utils"
+)
+
+// Counts n-gram计数, 可限制内存中保留的n-gram数, 多个分片的计数可合并
+type Counts struct {
+	words      *utils.StringCounter
+	sketch     *utils.CountMinSketch // 剪除的低频n-gram计数, 为空时直接丢弃
+	maxEntries int
+	sum        int
+	dropped    bool // sketch为空时已丢弃剪除的n-gram计数
+}
+
+// NewCounts 新建Counts, maxEntries为内存中最多保留的n-gram数, 0为不限制;
+// 超过时剪除低频n-gram, sketch不为空时其计数计入sketch用于近似查询
+func NewCounts(maxEntries int, sketch *utils.CountMinSketch) *Counts {
+	return &Counts{
+		words:      utils.NewStringCounter(nil),
+		sketch:     sketch,
+		maxEntries: maxEntries,
+	}
+}
+
+// Add 添加n-gram
+func (c *Counts) Add(words []string) {
+	c.words.Add(words)
+	c.sum += len(words)
+	c.prune()
+}
+
+// AddLine 切分一行文本并添加其中的n-gram
+func (c *Counts) AddLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	for _, sentence := range reChinese.Split(line, -1) {
+		sentence = strings.TrimSpace(sentence)
+		if sentence == "" {
+			continue
+		}
+		c.Add(ngrams([]rune(sentence)))
+	}
+}
+
+// Count n-gram的计数, 已剪除的部分为近似值
+func (c *Counts) Count(word string) int {
+	n := c.words.Count(word)
+	if c.sketch != nil {
+		n += c.sketch.Count(word)
+	}
+	return n
+}
+
+// Sum 全部n-gram的总计数, 包含已剪除的n-gram
+func (c *Counts) Sum() int {
+	return c.sum
+}
+
+// Len 内存中保留的n-gram数
+func (c *Counts) Len() int {
+	return c.words.Total()
+}
+
+// Iter 遍历内存中保留的n-gram
+func (c *Counts) Iter() <-chan utils.StringCounterItem {
+	return c.words.Iter()
+}
+
+// Merge 合并另一个分片的计数, 两者的sketch需大小相同;
+// 只有一方有sketch时, 另一方不能已丢弃剪除的计数, 只有other有sketch时复制其sketch
+func (c *Counts) Merge(other *Counts) error {
+	switch {
+	case c.sketch != nil && other.sketch != nil:
+		if err := c.sketch.Merge(other.sketch); err != nil {
+			return err
+		}
+	case c.sketch != nil && other.dropped, other.sketch != nil && c.dropped:
+		return errors.New("cannot merge counts that dropped pruned n-grams into counts with a count-min sketch")
+	case other.sketch != nil:
+		c.sketch = other.sketch.Clone()
+	default:
+		c.dropped = c.dropped || other.dropped
+	}
+	c.words.Merge(other.words)
+	c.sum += other.sum
+	c.prune()
+	return nil
+}
+
+// prune 超过maxEntries时剪除低频n-gram, 按计数从低到高剪除到只保留一半,
+// 由计数分布一次确定剪除的最大计数
+func (c *Counts) prune() {
+	total := c.words.Total()
+	if c.maxEntries <= 0 || total <= c.maxEntries {
+		return
+	}
+	histogram := c.words.Histogram()
+	counts := make([]int, 0, len(histogram))
+	for n := range histogram {
+		counts = append(counts, n)
+	}
+	sort.Ints(counts)
+	var (
+		target   = c.maxEntries / 2
+		maxCount int
+	)
+	for _, n := range counts {
+		if total <= target {
+			break
+		}
+		total -= histogram[n]
+		maxCount = n
+	}
+	for _, item := range c.words.Prune(maxCount) {
+		if c.sketch == nil {
+			c.dropped = true
+			continue
+		}
+		c.sketch.Add(item.Key, item.Value)
+	}
+}
+
+// ngrams 句子中长度为2到MAX_WORD_LENGTH+1的全部n-gram
+func ngrams(sentence []rune) []string {
+	var (
+		words         []string
+		maxWordLength = MAX_WORD_LENGTH + 1
+		l             = len(sentence)
+	)
+	for i := 0; i < l; i++ {
+		maxIdx := l - i + 1
+		if maxIdx > maxWordLength {
+			maxIdx = maxWordLength
+		}
+		for j := 1; j < maxIdx; j++ {
+			words = append(words, utils.RuneInRange(sentence, i, i+j+1))
+		}
+	}
+	return words
+}
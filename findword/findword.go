@@ -7,7 +7,7 @@ import (
 	"math"
 	"regexp"
 	"sort"
-	"strings"
+	"sync"
 
 	"github.com/bububa/jiagu/utils"
 )
@@ -21,23 +21,34 @@ const (
 	DEFAULT_MIN_ENTRO float64 = 3
 	// MAX_WORD_LENGTH 最大词长度
 	MAX_WORD_LENGTH int = 6
+	// DEFAULT_SKETCH_WIDTH 默认count-min sketch宽度
+	DEFAULT_SKETCH_WIDTH int = 1 << 20
+	// DEFAULT_SKETCH_DEPTH 默认count-min sketch深度
+	DEFAULT_SKETCH_DEPTH int = 4
 )
 
 var reChinese = regexp.MustCompile(`[[:punct:]，。、！？：；﹑•＂…‘’“”〝〞∕¦‖—　〈〉﹞﹝「」‹›〖〗】【»«』『〕〔》《﹐¸﹕︰﹔！¡？¿﹖﹌﹏﹋＇´ˊˋ―﹫︳︴¯＿￣﹢﹦﹤‐­˜﹟﹩﹠﹪﹡﹨﹍﹉﹎﹊ˇ︵︶︷︸︹︿﹀︺︽︾ˉ﹁﹂﹃﹄︻︼（）\\s+]+`)
 
 // Findword  根据文本利用信息熵新词发现
 type Findword struct {
-	minFreq  int
-	minMtro  float64
-	minEntro float64
+	minFreq     int
+	minMtro     float64
+	minEntro    float64
+	maxEntries  int
+	sketchWidth int
+	sketchDepth int
+	workers     int
 }
 
 // New 新建Findword
 func New() *Findword {
 	return &Findword{
-		minFreq:  DEFAULT_MIN_FREQ,
-		minMtro:  DEFAULT_MIN_MTRO,
-		minEntro: DEFAULT_MIN_ENTRO,
+		minFreq:     DEFAULT_MIN_FREQ,
+		minMtro:     DEFAULT_MIN_MTRO,
+		minEntro:    DEFAULT_MIN_ENTRO,
+		sketchWidth: DEFAULT_SKETCH_WIDTH,
+		sketchDepth: DEFAULT_SKETCH_DEPTH,
+		workers:     1,
 	}
 }
 
@@ -56,12 +67,89 @@ func (f *Findword) SetMinEntro(entro float64) {
 	f.minEntro = entro
 }
 
+// SetMaxEntries 设置每个分片内存中最多保留的n-gram数, 超过时剪除低频n-gram, 0为不限制
+func (f *Findword) SetMaxEntries(maxEntries int) {
+	f.maxEntries = maxEntries
+}
+
+// SetSketch 设置保存已剪除n-gram近似计数的count-min sketch大小, width为0时直接丢弃已剪除的计数
+func (f *Findword) SetSketch(width int, depth int) {
+	f.sketchWidth = width
+	f.sketchDepth = depth
+}
+
+// SetWorkers 设置并行计数的分片数, 每个分片单独计数后合并
+func (f *Findword) SetWorkers(workers int) {
+	f.workers = workers
+}
+
+// NewCounts 按当前设置新建分片计数
+func (f *Findword) NewCounts() *Counts {
+	var sketch *utils.CountMinSketch
+	if f.maxEntries > 0 && f.sketchWidth > 0 {
+		sketch = utils.NewCountMinSketch(f.sketchWidth, f.sketchDepth)
+	}
+	return NewCounts(f.maxEntries, sketch)
+}
+
 // Find 发现新词
 func (f *Findword) Find(input io.Reader) ([]utils.StringCounterItem, error) {
-	wordFreq, err := f.countWords(input)
+	wordFreq, err := f.Count(input)
 	if err != nil {
 		return nil, err
 	}
+	return f.FindCounts(wordFreq), nil
+}
+
+// Count 逐行读取文本统计n-gram, 按行分配到多个分片并行计数后合并
+func (f *Findword) Count(input io.Reader) (*Counts, error) {
+	workers := f.workers
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		shards = make([]*Counts, workers)
+		lines  = make(chan string, workers*64)
+		wg     sync.WaitGroup
+	)
+	for idx := range shards {
+		shards[idx] = f.NewCounts()
+		wg.Add(1)
+		go func(counts *Counts) {
+			defer wg.Done()
+			for line := range lines {
+				counts.AddLine(line)
+			}
+		}(shards[idx])
+	}
+	buf := bufio.NewReader(input)
+	var err error
+	for {
+		var line string
+		line, err = buf.ReadString('\n')
+		if line != "" {
+			lines <- line
+		}
+		if err != nil {
+			break
+		}
+	}
+	close(lines)
+	wg.Wait()
+	if !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	wordFreq := shards[0]
+	for _, shard := range shards[1:] {
+		if err := wordFreq.Merge(shard); err != nil {
+			return nil, err
+		}
+	}
+	return wordFreq, nil
+}
+
+// FindCounts 根据n-gram计数发现新词, 可用于合并多个文件的计数后统一计算
+func (f *Findword) FindCounts(wordFreq *Counts) []utils.StringCounterItem {
 	lDict, rDict := f.lrgInfo(wordFreq)
 	rEntroDict := calEntro(lDict)
 	lEntroDict := calEntro(rDict)
@@ -75,57 +163,13 @@ func (f *Findword) Find(input io.Reader) ([]utils.StringCounterItem, error) {
 		})
 	}
 	sort.Sort(sort.Reverse(words))
-	return words, nil
-}
-
-func (f *Findword) countWords(input io.Reader) (*utils.StringCounter, error) {
-	wordFreq := utils.NewStringCounter(nil)
-	maxWordLength := MAX_WORD_LENGTH + 1
-	buf := bufio.NewReader(input)
-	for {
-		line, err := buf.ReadString('\n')
-		if err != nil && errors.Is(err, io.EOF) {
-			break
-		} else if err != nil {
-			return nil, err
-		}
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		sentences := reChinese.Split(line, -1)
-		for _, sentence := range sentences {
-			sentence = strings.TrimSpace(sentence)
-			if sentence == "" {
-				continue
-			}
-			var words []string
-			sentenceRune := []rune(sentence)
-			l := len(sentenceRune)
-			var i int
-			for i < l {
-				maxIdx := l - i + 1
-				if maxIdx > maxWordLength {
-					maxIdx = maxWordLength
-				}
-				var j int = 1
-				for j < maxIdx {
-					word := utils.RuneInRange(sentenceRune, i, i+j+1)
-					words = append(words, word)
-					j++
-				}
-				i++
-			}
-			wordFreq.Add(words)
-		}
-	}
-	return wordFreq, nil
+	return words
 }
 
-func (f *Findword) lrgInfo(wordFreq *utils.StringCounter) (map[string][]int, map[string][]int) {
+func (f *Findword) lrgInfo(wordFreq *Counts) (map[string][]int, map[string][]int) {
 	var (
 		totalSum   = wordFreq.Sum()
-		totalWords = wordFreq.Total()
+		totalWords = wordFreq.Len()
 		leftDict   = make(map[string][]int, totalWords)
 		rightDict  = make(map[string][]int, totalWords)
 	)
@@ -145,7 +189,7 @@ func (f *Findword) lrgInfo(wordFreq *utils.StringCounter) (map[string][]int, map
 	return leftDict, rightDict
 }
 
-func (f *Findword) updateDict(wordFreq *utils.StringCounter, totalSum int, freq int, sideDict map[string][]int, sideWord []rune, sideFreq int) map[string][]int {
+func (f *Findword) updateDict(wordFreq *Counts, totalSum int, freq int, sideDict map[string][]int, sideWord []rune, sideFreq int) map[string][]int {
 	if sideFreq <= f.minFreq {
 		return sideDict
 	}
@@ -171,7 +215,7 @@ func (f *Findword) updateDict(wordFreq *utils.StringCounter, totalSum int, freq
 	return sideDict
 }
 
-func (f *Findword) entroFilter(wordFreq *utils.StringCounter, entroInRLDict map[string][2]float64, entroInLDict map[string]float64, entroInRDict map[string]float64) map[string]int {
+func (f *Findword) entroFilter(wordFreq *Counts, entroInRLDict map[string][2]float64, entroInLDict map[string]float64, entroInRDict map[string]float64) map[string]int {
 	entroDict := make(map[string]int)
 	for word, entro := range entroInRLDict {
 		if entro[0] > f.minEntro && entro[1] > f.minEntro {
@@ -2,7 +2,11 @@ package jiagu
 
 import (
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/bububa/jiagu/findword"
+	"github.com/bububa/jiagu/utils"
 )
 
 // TestFindword 测试发现新词
@@ -22,3 +26,77 @@ func TestFindword(t *testing.T) {
 	}
 	t.Logf("%+v\n", words)
 }
+
+// TestFindwordCounts 测试分片计数合并
+func TestFindwordCounts(t *testing.T) {
+	content, err := os.ReadFile("./data/findword/input.txt")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	lines := strings.Split(string(content), "\n")
+	half := len(lines) / 2
+	model := findword.New()
+	model.SetWorkers(4)
+	whole, err := model.Count(strings.NewReader(string(content)))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	merged, err := model.Count(strings.NewReader(strings.Join(lines[:half], "\n")))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	shard, err := model.Count(strings.NewReader(strings.Join(lines[half:], "\n")))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := merged.Merge(shard); err != nil {
+		t.Error(err)
+		return
+	}
+	if merged.Sum() != whole.Sum() || merged.Len() != whole.Len() {
+		t.Errorf("result: %d/%d, expect: %d/%d\n", merged.Sum(), merged.Len(), whole.Sum(), whole.Len())
+		return
+	}
+	for item := range whole.Iter() {
+		if n := merged.Count(item.Key); n != item.Value {
+			t.Errorf("%s result: %d, expect: %d\n", item.Key, n, item.Value)
+			break
+		}
+	}
+	model.SetMaxEntries(1000)
+	bounded, err := model.Count(strings.NewReader(string(content)))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if bounded.Len() > 1000 || bounded.Sum() != whole.Sum() {
+		t.Errorf("result: %d/%d, expect at most 1000 entries\n", bounded.Sum(), bounded.Len())
+	}
+	// 保留的最高频n-gram计数准确, 剪除的n-gram计数不低于实际计数
+	var top utils.StringCounterItem
+	for item := range whole.Iter() {
+		if item.Value > top.Value {
+			top = item
+		}
+		if n := bounded.Count(item.Key); n < item.Value {
+			t.Errorf("%s result: %d, expect at least: %d\n", item.Key, n, item.Value)
+			break
+		}
+	}
+	var retained bool
+	for item := range bounded.Iter() {
+		if item.Key == top.Key {
+			retained = true
+			if item.Value != top.Value {
+				t.Errorf("%s result: %d, expect: %d\n", item.Key, item.Value, top.Value)
+			}
+		}
+	}
+	if !retained {
+		t.Errorf("%s expect to be retained\n", top.Key)
+	}
+}
@@ -23,6 +23,12 @@ const (
 	VOCAB_DICT = "jiagu.dict"
 	// STOPWORDS stopwords字典
 	STOPWORDS_DICT = "stopwords.txt"
+	// SENTIMENT_DICT 情感词典
+	SENTIMENT_DICT = "sentiment.dict"
+	// NEGATION_DICT 否定词词典
+	NEGATION_DICT = "negation.dict"
+	// DEGREE_DICT 程度副词词典
+	DEGREE_DICT = "degree.dict"
 )
 
 //go:embed model/*
@@ -47,11 +53,14 @@ func initPerceptron(modelFile string) (*perceptron.Perceptron, error) {
 
 func Init() {
 	NerModel()
+	NerRecognizer()
 	PosModel()
 	Stopwords()
 	Segment()
 	KeywordsInstance()
+	TFIDFInstance()
 	SummarizeInstance()
 	KnowledgeInstance()
 	SentimentInstance()
+	SentimentAnalyzer()
 }
@@ -1,10 +1,15 @@
 package jiagu
 
 import (
+	"sync"
+
 	"github.com/bububa/jiagu/textrank"
 )
 
-var keywordsModel *textrank.Keywords
+var (
+	keywordsModel   *textrank.Keywords
+	keywordsPosOnce sync.Once
+)
 
 // KeywordsInstance get keywordsModel singleton
 func KeywordsInstance() *textrank.Keywords {
@@ -14,8 +19,31 @@ func KeywordsInstance() *textrank.Keywords {
 	return keywordsModel
 }
 
-// Keywords 关键词提取
-func Keywords(txt string, n int) []string {
+// Keywords 关键词提取, allowedPOS不为空时只保留指定词性的词, 如textrank.DefaultAllowedPOS
+func Keywords(txt string, n int, allowedPOS ...string) []string {
+	model := keywordsWithPOS(allowedPOS)
+	return model.Extract(txt, n, allowedPOS...)
+}
+
+// KeywordsWithScores 关键词提取, 包含归一化得分及词频
+func KeywordsWithScores(txt string, n int, allowedPOS ...string) []textrank.Keyword {
+	model := keywordsWithPOS(allowedPOS)
+	return model.ExtractWithScores(txt, n, allowedPOS...)
+}
+
+// Keyphrases 关键短语提取, 合并原文中紧邻的高分词, 包含得分及在原文中的字符位置
+func Keyphrases(txt string, n int, allowedPOS ...string) []textrank.Keyphrase {
+	model := keywordsWithPOS(allowedPOS)
+	return model.ExtractPhrases(txt, n, allowedPOS...)
+}
+
+// keywordsWithPOS 按词性过滤时才加载词性标注模型, 只设置一次
+func keywordsWithPOS(allowedPOS []string) *textrank.Keywords {
 	model := KeywordsInstance()
-	return model.Extract(txt, n)
+	if len(allowedPOS) > 0 {
+		keywordsPosOnce.Do(func() {
+			model.SetPosModel(PosModel())
+		})
+	}
+	return model
 }
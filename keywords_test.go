@@ -1,7 +1,11 @@
 package jiagu
 
 import (
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/bububa/jiagu/textrank"
 )
 
 // TestKeywords 测试关键词提取
@@ -14,7 +18,7 @@ NASA文章介绍，在中国为全球绿化进程做出的贡献中，有42%来
 据观察者网过往报道，2017年我国全国共完成造林736.2万公顷、森林抚育830.2万公顷。其中，天然林资源保护工程完成造林26万公顷，退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。三北及长江流域等重点防护林体系工程完成造林99.1万公顷。完成国家储备林建设任务68万公顷。
 	`
 	expects := []string{
-		"公顷", "工程", "造林", "绿化", "数据",
+		"工程", "完成", "公顷", "造林", "绿化",
 	}
 	kws := Keywords(txt, 5)
 	if len(kws) != len(expects) {
@@ -28,3 +32,65 @@ NASA文章介绍，在中国为全球绿化进程做出的贡献中，有42%来
 		}
 	}
 }
+
+// TestKeywordsWithScores 测试提取关键词及得分
+func TestKeywordsWithScores(t *testing.T) {
+	txt := "据观察者网过往报道，2017年我国全国共完成造林736.2万公顷、森林抚育830.2万公顷。其中，天然林资源保护工程完成造林26万公顷，退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。"
+	kws := KeywordsWithScores(txt, 3)
+	words := Keywords(txt, 3)
+	if len(kws) != len(words) || len(kws) == 0 {
+		t.Errorf("result: %+v, expect: %+v\n", kws, words)
+		return
+	}
+	if kws[0].Score != 1 {
+		t.Errorf("expects top score:1, result: %+v\n", kws)
+	}
+	for idx, kw := range kws {
+		if kw.Word != words[idx] || kw.Freq == 0 || (idx > 0 && kw.Score > kws[idx-1].Score) {
+			t.Errorf("result: %+v, expect: %+v\n", kws, words)
+			break
+		}
+	}
+	merged := textrank.MergeKeywords([][]textrank.Keyword{
+		{{Word: "造林", Score: 1, Freq: 3}, {Word: "公顷", Score: 0.5, Freq: 2}},
+		{{Word: "公顷", Score: 1, Freq: 1}},
+	}, 1)
+	if len(merged) != 1 || merged[0].Word != "公顷" || merged[0].Score != 0.75 || merged[0].Freq != 3 {
+		t.Errorf("result: %+v\n", merged)
+	}
+}
+
+// TestKeywordsPOS 测试按词性过滤关键词
+func TestKeywordsPOS(t *testing.T) {
+	txt := "据观察者网过往报道，2017年我国全国共完成造林736.2万公顷、森林抚育830.2万公顷。其中，天然林资源保护工程完成造林26万公顷，退耕还林工程完成造林91.2万公顷。"
+	kws := Keywords(txt, 5, textrank.DefaultAllowedPOS...)
+	if len(kws) == 0 {
+		t.Errorf("expects keywords, result: %+v\n", kws)
+	}
+	for _, w := range kws {
+		if _, err := strconv.ParseFloat(w, 64); err == nil {
+			t.Errorf("unexpected numeral keyword: %s, result: %+v\n", w, kws)
+		}
+	}
+}
+
+// TestKeyphrases 测试关键短语提取
+func TestKeyphrases(t *testing.T) {
+	txt := "人工智能芯片是当前的热点。多家公司发布了人工智能芯片，人工智能芯片市场快速增长。\n芯片公司竞争激烈。"
+	phrases := Keyphrases(txt, 5)
+	if len(phrases) == 0 || phrases[0].Score != 1 {
+		t.Errorf("result: %+v\n", phrases)
+		return
+	}
+	runes := []rune(txt)
+	for _, phrase := range phrases {
+		if phrase.Freq != len(phrase.Offsets) || strings.Join(phrase.Words, "") != phrase.Phrase {
+			t.Errorf("result: %+v\n", phrase)
+		}
+		for _, offset := range phrase.Offsets {
+			if text := string(runes[offset.Start:offset.End]); text != phrase.Phrase {
+				t.Errorf("offset: %+v, expect: %s, result: %s\n", offset, phrase.Phrase, text)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/bububa/jiagu/knowledge"
+	"github.com/bububa/jiagu/sentence"
 )
 
 var knowledgeModel *knowledge.Knowledge
@@ -33,3 +34,23 @@ func Knowledge(txt string) []knowledge.Entity {
 	words := Seg(txt)
 	return model.Entities(words)
 }
+
+// KnowledgeGraph 从语料中提取三元组并构建知识图谱
+func KnowledgeGraph(corpus []string) *knowledge.Graph {
+	graph := knowledge.NewGraph()
+	for _, txt := range corpus {
+		graph.Add(Knowledge(txt))
+	}
+	return graph
+}
+
+// KnowledgeDocument 文档级知识图谱关系提取, 分句后处理代词及零主语句
+func KnowledgeDocument(txt string) []knowledge.SentenceEntity {
+	model := KnowledgeInstance()
+	sentences := sentence.Texts(txt)
+	words := make([][]string, 0, len(sentences))
+	for _, sent := range sentences {
+		words = append(words, Seg(sent))
+	}
+	return model.Document(words)
+}
@@ -0,0 +1,127 @@
+package knowledge
+
+import (
+	"strings"
+)
+
+// DefaultPronouns 默认代词, 作为主体时使用上文主体替换
+var DefaultPronouns = []string{
+	"他", "她", "它", "他们", "她们", "它们", "其",
+	"该公司", "该集团", "该企业", "该机构", "该组织", "该校", "该院", "该团队", "该品牌", "该产品",
+	"此人",
+}
+
+// SentenceEntity 带来源句子的三元组
+type SentenceEntity struct {
+	Entity
+	Sentence      string `json:"sentence,omitempty"`
+	SentenceIndex int    `json:"sentence_index"`
+	Resolved      bool   `json:"resolved,omitempty"` // 主体来自上文(代词消解或零主语句)
+}
+
+// SetPronouns 设置代词列表
+func (k *Knowledge) SetPronouns(pronouns []string) {
+	k.pronouns = make(map[string]struct{}, len(pronouns))
+	k.AddPronouns(pronouns)
+}
+
+// AddPronouns 添加代词
+func (k *Knowledge) AddPronouns(pronouns []string) {
+	if k.pronouns == nil {
+		k.pronouns = make(map[string]struct{}, len(pronouns))
+	}
+	for _, w := range pronouns {
+		k.pronouns[w] = struct{}{}
+	}
+}
+
+// IsPronoun 判断是否为代词
+func (k *Knowledge) IsPronoun(word string) bool {
+	_, found := k.pronouns[word]
+	return found
+}
+
+// Document 文档级三元组提取, sentences为分句后的分词结果
+// 主体为代词或句子没有主体时, 使用最近一个句子中出现的主体
+// 零主语句的三元组主体位置为-1
+func (k *Knowledge) Document(sentences [][]string) []SentenceEntity {
+	var (
+		ret     []SentenceEntity
+		carried *Subject
+	)
+	for sentIdx, words := range sentences {
+		if len(words) == 0 {
+			continue
+		}
+		labels := k.model.PredictProb(words)
+		spans, objects := lab2spans(words, labels)
+		var (
+			subjects []Subject
+			latest   *Subject
+		)
+		for _, subject := range spans {
+			if !k.IsPronoun(subject.Word) {
+				subjects = append(subjects, subject)
+				latest = &subjects[len(subjects)-1]
+				continue
+			}
+			if carried == nil {
+				continue
+			}
+			subject.Word = carried.Word
+			subjects = append(subjects, subject)
+		}
+		if len(subjects) == 0 && carried != nil && len(objects) > 0 {
+			subject := k.pronounSubject(words, objects[0].Index)
+			subject.Word = carried.Word
+			subject.Confidence = carried.Confidence
+			subjects = append(subjects, subject)
+		}
+		sentence := strings.Join(words, "")
+		runes := []rune(sentence)
+		if len(subjects) > 0 {
+			for _, e := range pairSpans(words, subjects, objects) {
+				ret = append(ret, SentenceEntity{
+					Entity:        e,
+					Sentence:      sentence,
+					SentenceIndex: sentIdx,
+					Resolved:      e.SubjectStart < 0 || string(runes[e.SubjectStart:e.SubjectEnd]) != e.Subject,
+				})
+			}
+		}
+		if latest != nil {
+			subject := *latest
+			carried = &subject
+		}
+	}
+	return ret
+}
+
+// pronounSubject 在属性值之前查找代词作为主体位置, 未找到时返回位置为-1的主体
+func (k *Knowledge) pronounSubject(words []string, before int) Subject {
+	var offset int
+	for idx, word := range words {
+		if idx >= before {
+			break
+		}
+		wordLen := len([]rune(word))
+		if k.IsPronoun(word) {
+			return Subject{
+				Word:      word,
+				Label:     "实体",
+				Index:     idx,
+				End:       idx + 1,
+				CharStart: offset,
+				CharEnd:   offset + wordLen,
+			}
+		}
+		offset += wordLen
+	}
+	return Subject{
+		Label:     "实体",
+		Index:     -1,
+		End:       0,
+		CharStart: -1,
+		CharEnd:   -1,
+	}
+}
@@ -0,0 +1,158 @@
+package knowledge
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DEFAULT_BASE_IRI 默认IRI前缀
+const DEFAULT_BASE_IRI = "urn:jiagu:"
+
+// WriteNTriples 导出为N-Triples格式, 主体和属性为IRI, 属性值为字符串
+func (g *Graph) WriteNTriples(w io.Writer, base string) error {
+	base = baseIRI(base)
+	for _, t := range g.Triples() {
+		if _, err := fmt.Fprintf(w, "<%s> <%s> %s .\n", entityIRI(base, t.Subject), attributeIRI(base, t.Attribute), literal(t.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTurtle 导出为Turtle格式, 按主体分组
+func (g *Graph) WriteTurtle(w io.Writer, base string) error {
+	base = baseIRI(base)
+	subjects, groups := g.groupBySubject()
+	for _, subject := range subjects {
+		triples := groups[subject]
+		if _, err := fmt.Fprintf(w, "<%s>", entityIRI(base, subject)); err != nil {
+			return err
+		}
+		for idx, t := range triples {
+			sep := " ;\n   "
+			if idx == 0 {
+				sep = " "
+			}
+			if _, err := fmt.Fprintf(w, "%s<%s> %s", sep, attributeIRI(base, t.Attribute), literal(t.Value)); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, " .\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSONLD 导出为JSON-LD格式
+func (g *Graph) WriteJSONLD(w io.Writer, base string) error {
+	base = baseIRI(base)
+	subjects, groups := g.groupBySubject()
+	nodes := make([]map[string]interface{}, 0, len(subjects))
+	for _, subject := range subjects {
+		node := map[string]interface{}{
+			"@id":   entityIRI(base, subject),
+			"label": subject,
+		}
+		for _, t := range groups[subject] {
+			values, _ := node[t.Attribute].([]string)
+			node[t.Attribute] = append(values, t.Value)
+		}
+		nodes = append(nodes, node)
+	}
+	doc := map[string]interface{}{
+		"@context": map[string]interface{}{
+			"@vocab": base + "attribute:",
+			"label":  "http://www.w3.org/2000/01/rdf-schema#label",
+		},
+		"@graph": nodes,
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// WriteCSV 导出为CSV格式, 包含表头
+func (g *Graph) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"subject", "attribute", "value", "count"}); err != nil {
+		return err
+	}
+	for _, t := range g.Triples() {
+		if err := writer.Write([]string{t.Subject, t.Attribute, t.Value, strconv.Itoa(t.Count)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (g *Graph) groupBySubject() ([]string, map[string][]Triple) {
+	var subjects []string
+	groups := make(map[string][]Triple)
+	for _, t := range g.Triples() {
+		if _, found := groups[t.Subject]; !found {
+			subjects = append(subjects, t.Subject)
+		}
+		groups[t.Subject] = append(groups[t.Subject], t)
+	}
+	return subjects, groups
+}
+
+func baseIRI(base string) string {
+	if base == "" {
+		return DEFAULT_BASE_IRI
+	}
+	return base
+}
+
+func entityIRI(base string, term string) string {
+	return base + "entity:" + escapeIRI(term)
+}
+
+func attributeIRI(base string, term string) string {
+	return base + "attribute:" + escapeIRI(term)
+}
+
+// escapeIRI 转义IRI中不允许出现的字符
+func escapeIRI(term string) string {
+	var buf strings.Builder
+	for _, ch := range term {
+		if ch <= 0x20 || strings.ContainsRune("<>\"{}|^`\\%", ch) {
+			for _, b := range []byte(string(ch)) {
+				fmt.Fprintf(&buf, "%%%02X", b)
+			}
+			continue
+		}
+		buf.WriteRune(ch)
+	}
+	return buf.String()
+}
+
+// literal 转义字符串字面量
+func literal(value string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, ch := range value {
+		switch ch {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			buf.WriteRune(ch)
+		}
+	}
+	buf.WriteString(`"@zh`)
+	return buf.String()
+}
@@ -0,0 +1,136 @@
+package knowledge
+
+import (
+	"sync"
+)
+
+// Triple 三元组及出现次数
+type Triple struct {
+	Subject   string `json:"subject,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Count     int    `json:"count,omitempty"`
+}
+
+func (t Triple) String() string {
+	return t.Subject + t.Attribute + t.Value
+}
+
+type tripleKey struct {
+	subject   string
+	attribute string
+	value     string
+}
+
+// Graph 内存三元组存储, 相同三元组去重并计数
+type Graph struct {
+	triples     []Triple
+	index       map[tripleKey]int
+	bySubject   map[string][]int
+	byAttribute map[string][]int
+	byValue     map[string][]int
+	locker      *sync.RWMutex
+}
+
+// NewGraph 新建Graph
+func NewGraph() *Graph {
+	return &Graph{
+		index:       make(map[tripleKey]int),
+		bySubject:   make(map[string][]int),
+		byAttribute: make(map[string][]int),
+		byValue:     make(map[string][]int),
+		locker:      new(sync.RWMutex),
+	}
+}
+
+// Add 添加三元组
+func (g *Graph) Add(entities []Entity) {
+	g.locker.Lock()
+	defer g.locker.Unlock()
+	for _, e := range entities {
+		key := tripleKey{
+			subject:   e.Subject,
+			attribute: e.Attribute,
+			value:     e.Value,
+		}
+		if idx, found := g.index[key]; found {
+			g.triples[idx].Count++
+			continue
+		}
+		idx := len(g.triples)
+		g.triples = append(g.triples, Triple{
+			Subject:   e.Subject,
+			Attribute: e.Attribute,
+			Value:     e.Value,
+			Count:     1,
+		})
+		g.index[key] = idx
+		g.bySubject[e.Subject] = append(g.bySubject[e.Subject], idx)
+		g.byAttribute[e.Attribute] = append(g.byAttribute[e.Attribute], idx)
+		g.byValue[e.Value] = append(g.byValue[e.Value], idx)
+	}
+}
+
+// Len 三元组数量(去重后)
+func (g *Graph) Len() int {
+	g.locker.RLock()
+	defer g.locker.RUnlock()
+	return len(g.triples)
+}
+
+// Triples 按添加顺序返回全部三元组
+func (g *Graph) Triples() []Triple {
+	g.locker.RLock()
+	defer g.locker.RUnlock()
+	ret := make([]Triple, len(g.triples))
+	copy(ret, g.triples)
+	return ret
+}
+
+// Count 获取三元组出现次数
+func (g *Graph) Count(subject string, attribute string, value string) int {
+	g.locker.RLock()
+	defer g.locker.RUnlock()
+	if idx, found := g.index[tripleKey{subject: subject, attribute: attribute, value: value}]; found {
+		return g.triples[idx].Count
+	}
+	return 0
+}
+
+// Query 查询三元组, 参数为空字符串时匹配任意值
+func (g *Graph) Query(subject string, attribute string, value string) []Triple {
+	g.locker.RLock()
+	defer g.locker.RUnlock()
+	var candidates []int
+	if subject == "" && attribute == "" && value == "" {
+		candidates = make([]int, len(g.triples))
+		for idx := range candidates {
+			candidates[idx] = idx
+		}
+	} else {
+		var found bool
+		for _, q := range []struct {
+			term  string
+			index map[string][]int
+		}{
+			{subject, g.bySubject},
+			{attribute, g.byAttribute},
+			{value, g.byValue},
+		} {
+			if q.term == "" {
+				continue
+			}
+			if list := q.index[q.term]; !found || len(list) < len(candidates) {
+				candidates, found = list, true
+			}
+		}
+	}
+	var ret []Triple
+	for _, idx := range candidates {
+		t := g.triples[idx]
+		if (subject == "" || t.Subject == subject) && (attribute == "" || t.Attribute == attribute) && (value == "" || t.Value == value) {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
@@ -3,6 +3,7 @@ package knowledge
 import (
 	"io"
 
+	"github.com/bububa/jiagu/ner"
 	"github.com/bububa/jiagu/perceptron"
 	pmodel "github.com/bububa/jiagu/perceptron/model"
 	"github.com/bububa/jiagu/utils"
@@ -10,14 +11,17 @@ import (
 
 // Knowledge 知识图谱关系提取
 type Knowledge struct {
-	model *perceptron.Perceptron
+	model    *perceptron.Perceptron
+	pronouns map[string]struct{}
 }
 
 // New 新建图谱关系
 func New(model *perceptron.Perceptron) *Knowledge {
-	return &Knowledge{
+	k := &Knowledge{
 		model: model,
 	}
+	k.SetPronouns(DefaultPronouns)
+	return k
 }
 
 // NewFromReader 从model新建
@@ -26,73 +30,120 @@ func NewFromReader(r io.Reader) (*Knowledge, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Knowledge{
-		model: aModel,
-	}, nil
+	return New(aModel), nil
 }
 
+// Entities 提取三元组, 字符位置基于words拼接后的文本
 func (k *Knowledge) Entities(words []string) []Entity {
-	labels := k.model.Predict(words)
+	labels := k.model.PredictProb(words)
 	return lab2spo(words, labels)
 }
 
+// clauseDelimiters 分句标点, 属性值优先匹配同一分句内的主体
+var clauseDelimiters = map[string]struct{}{
+	"，": {},
+	",": {},
+	"。": {},
+	"；": {},
+	";": {},
+	"！": {},
+	"？": {},
+}
+
 func lab2spo(words []string, eppLabels []pmodel.Class) []Entity {
+	subjects, objects := lab2spans(words, eppLabels)
+	if len(subjects) == 0 || len(objects) == 0 {
+		return nil
+	}
+	return pairSpans(words, subjects, objects)
+}
+
+// lab2spans 根据标签拆分主体及属性值片段
+func lab2spans(words []string, eppLabels []pmodel.Class) ([]Subject, []Subject) {
 	var (
 		subjects []Subject
 		objects  []Subject
-		entities []Entity
 	)
-	var index int
-	for idx, word := range words {
-		ep := eppLabels[idx].Label
-		epPrefix := ep[0]
-		var label string
-		if len(ep) > 2 {
-			label = ep[2:]
+	for _, span := range ner.Decode(words, eppLabels) {
+		subject := Subject{
+			Word:       span.Text,
+			Label:      span.Type,
+			Index:      span.Start,
+			End:        span.End,
+			CharStart:  span.CharStart,
+			CharEnd:    span.CharEnd,
+			Confidence: span.Confidence,
 		}
-		if epPrefix == 'B' && label == "实体" {
-			subjects = append(subjects, Subject{
-				Word:  word,
-				Label: label,
-				Index: index,
-			})
-		} else if (epPrefix == 'I' || epPrefix == 'E') && label == "实体" {
-			l := len(subjects)
-			if l == 0 {
-				continue
-			}
-			subjects[l-1].Word += word
+		if span.Type == "实体" {
+			subjects = append(subjects, subject)
+		} else {
+			objects = append(objects, subject)
 		}
+	}
+	return subjects, objects
+}
+
+// pairSpans 为每个属性值匹配主体
+func pairSpans(words []string, subjects []Subject, objects []Subject) []Entity {
+	clauses := make([]int, len(words)+1)
+	for idx, word := range words {
+		clauses[idx+1] = clauses[idx]
+		if _, found := clauseDelimiters[word]; found {
+			clauses[idx+1]++
+		}
+	}
+	entities := make([]Entity, 0, len(objects))
+	for _, obj := range objects {
+		subject := nearestSubject(subjects, obj, clauses)
+		entities = append(entities, newEntity(subject, obj))
+	}
+	return entities
+}
+
+func newEntity(subject Subject, obj Subject) Entity {
+	return Entity{
+		Subject:      subject.Word,
+		Attribute:    utils.StringInRange(obj.Label, 0, -1),
+		Value:        obj.Word,
+		SubjectStart: subject.CharStart,
+		SubjectEnd:   subject.CharEnd,
+		ValueStart:   obj.CharStart,
+		ValueEnd:     obj.CharEnd,
+		Confidence:   subject.Confidence * obj.Confidence,
+	}
+}
 
-		if epPrefix == 'B' && label != "实体" {
-			objects = append(objects, Subject{
-				Word:  word,
-				Label: label,
-				Index: index,
-			})
-		} else if (epPrefix == 'I' || epPrefix == 'E') && label != "实体" {
-			l := len(objects)
-			if l == 0 {
-				continue
-			}
-			objects[l-1].Word += word
+// nearestSubject 为属性值选择主体: 优先同一分句内前面最近的主体, 其次前面最近的主体, 最后后面最近的主体
+func nearestSubject(subjects []Subject, obj Subject, clauses []int) Subject {
+	var (
+		preceding = -1
+		following = -1
+	)
+	for idx, subject := range subjects {
+		if subject.End <= obj.Index {
+			preceding = idx
+		} else if following < 0 && subject.Index >= obj.End {
+			following = idx
 		}
-		index++
 	}
-	subL := len(subjects)
-	objL := len(objects)
-	if subL == 0 || objL == 0 {
-		return entities
+	clauseOf := func(idx int) int {
+		if idx < 0 {
+			return clauses[0]
+		}
+		return clauses[idx]
 	}
-	if subL == 1 {
-		for _, obj := range objects {
-			predicate := utils.StringInRange(obj.Label, 0, -1)
-			entities = append(entities, Entity{
-				Subject:   subjects[0].Word,
-				Attribute: predicate,
-				Value:     obj.Word,
-			})
+	if preceding >= 0 {
+		subject := subjects[preceding]
+		if clauseOf(subject.Index) == clauses[obj.Index] || following < 0 {
+			return subject
 		}
+		if next := subjects[following]; clauses[next.Index] == clauses[obj.Index] {
+			return next
+		}
+		return subject
 	}
-	return entities
+	if following >= 0 {
+		return subjects[following]
+	}
+	return subjects[0]
 }
@@ -2,16 +2,25 @@ package knowledge
 
 // Subject model subject
 type Subject struct {
-	Word  string
-	Label string
-	Index int
+	Word       string
+	Label      string
+	Index      int     // 起始token位置
+	End        int     // 结束token位置(不包含)
+	CharStart  int     // 起始字符位置
+	CharEnd    int     // 结束字符位置(不包含)
+	Confidence float64 // 各token置信度平均值
 }
 
 // Entity entity result
 type Entity struct {
-	Subject   string `json:"subject,omitempty"`
-	Attribute string `json:"attribute,omitempty"`
-	Value     string `json:"value,omitempty"`
+	Subject      string  `json:"subject,omitempty"`
+	Attribute    string  `json:"attribute,omitempty"`
+	Value        string  `json:"value,omitempty"`
+	SubjectStart int     `json:"subject_start"` // 主体起始字符位置
+	SubjectEnd   int     `json:"subject_end"`   // 主体结束字符位置(不包含)
+	ValueStart   int     `json:"value_start"`   // 属性值起始字符位置
+	ValueEnd     int     `json:"value_end"`     // 属性值结束字符位置(不包含)
+	Confidence   float64 `json:"confidence"`    // 主体与属性值置信度乘积
 }
 
 func (e Entity) String() string {
@@ -1,7 +1,10 @@
 package jiagu
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/bububa/jiagu/knowledge"
 )
 
 // TestKnowledge 测试知识图谱
@@ -24,3 +27,82 @@ func TestKnowledge(t *testing.T) {
 		}
 	}
 }
+
+// TestKnowledgeOffsets 测试三元组位置及置信度
+func TestKnowledgeOffsets(t *testing.T) {
+	txt := "姚明1980年9月12日出生于上海市徐汇区，叶莉1981年10月20日出生于上海市。"
+	runes := []rune(strings.Join(Seg(txt), ""))
+	entities := Knowledge(txt)
+	if len(entities) == 0 {
+		t.Error("expect entities")
+		return
+	}
+	subjects := make(map[string]struct{})
+	for _, e := range entities {
+		if string(runes[e.SubjectStart:e.SubjectEnd]) != e.Subject || string(runes[e.ValueStart:e.ValueEnd]) != e.Value {
+			t.Errorf("invalid offsets: %+v\n", e)
+		}
+		if e.Confidence <= 0 || e.Confidence > 1 {
+			t.Errorf("invalid confidence: %+v\n", e)
+		}
+		subjects[e.Subject] = struct{}{}
+	}
+	if len(subjects) < 2 {
+		t.Errorf("result: %+v, expect multiple subjects\n", entities)
+	}
+}
+
+// TestKnowledgeGraph 测试知识图谱存储及导出
+func TestKnowledgeGraph(t *testing.T) {
+	graph := knowledge.NewGraph()
+	graph.Add([]knowledge.Entity{
+		{Subject: "姚明", Attribute: "出生地", Value: "上海市徐汇区"},
+		{Subject: "姚明", Attribute: "祖籍", Value: "江苏省苏州市吴江区震泽镇"},
+		{Subject: "姚明", Attribute: "出生地", Value: "上海市徐汇区"},
+		{Subject: "叶莉", Attribute: "出生地", Value: "上海市"},
+	})
+	if graph.Len() != 3 {
+		t.Errorf("result: %d, expect: 3\n", graph.Len())
+	}
+	if n := graph.Count("姚明", "出生地", "上海市徐汇区"); n != 2 {
+		t.Errorf("result: %d, expect: 2\n", n)
+	}
+	if triples := graph.Query("", "出生地", ""); len(triples) != 2 {
+		t.Errorf("result: %+v, expect 2 triples\n", triples)
+	}
+	if triples := graph.Query("叶莉", "祖籍", ""); len(triples) != 0 {
+		t.Errorf("result: %+v, expect 0 triple\n", triples)
+	}
+	var buf strings.Builder
+	if err := graph.WriteNTriples(&buf, ""); err != nil {
+		t.Error(err)
+		return
+	}
+	expect := "<urn:jiagu:entity:叶莉> <urn:jiagu:attribute:出生地> \"上海市\"@zh .\n"
+	if lines := strings.SplitAfter(buf.String(), "\n"); len(lines) != 4 || lines[2] != expect {
+		t.Errorf("result: %s, expect: %s\n", buf.String(), expect)
+	}
+	buf.Reset()
+	if err := graph.WriteCSV(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(buf.String(), "姚明,出生地,上海市徐汇区,2\n") {
+		t.Errorf("result: %s\n", buf.String())
+	}
+}
+
+// TestKnowledgeDocument 测试文档级知识图谱
+func TestKnowledgeDocument(t *testing.T) {
+	txt := "姚明1980年9月12日出生于上海市徐汇区。他祖籍江苏省苏州市吴江区震泽镇。"
+	entities := KnowledgeDocument(txt)
+	var found bool
+	for _, e := range entities {
+		if e.SentenceIndex == 1 && e.String() == "姚明祖籍江苏省苏州市吴江区震泽镇" {
+			found = e.Resolved
+		}
+	}
+	if !found {
+		t.Errorf("result: %+v, expect: 姚明祖籍江苏省苏州市吴江区震泽镇\n", entities)
+	}
+}
@@ -1,11 +1,18 @@
 package jiagu
 
 import (
+	"io"
+
+	"github.com/bububa/jiagu/ner"
 	"github.com/bububa/jiagu/perceptron"
 	"github.com/bububa/jiagu/perceptron/model"
+	"github.com/bububa/jiagu/utils"
 )
 
-var nerModel *perceptron.Perceptron
+var (
+	nerModel      *perceptron.Perceptron
+	nerRecognizer *ner.Recognizer
+)
 
 // NerModel get nerModel singleton
 func NerModel() *perceptron.Perceptron {
@@ -18,8 +25,61 @@ func NerModel() *perceptron.Perceptron {
 	return nerModel
 }
 
+// NerRecognizer get nerRecognizer singleton
+func NerRecognizer() *ner.Recognizer {
+	if nerRecognizer == nil {
+		nerRecognizer = ner.NewRecognizer(NerModel(), nil)
+	}
+	return nerRecognizer
+}
+
 // Ner 命名实体识别
 func Ner(words []string) []model.Class {
 	NerModel()
 	return nerModel.Predict(words)
 }
+
+// NerEntities 命名实体识别, 返回实体片段(合并实体词典结果)
+func NerEntities(words []string) []ner.Entity {
+	recognizer := NerRecognizer()
+	return recognizer.Entities(words)
+}
+
+// NerCharEntities 按字命名实体识别, 字符位置对应原文本
+func NerCharEntities(txt string) []ner.Entity {
+	return NerEntities(utils.StringSplit(txt))
+}
+
+// NerTextEntities 分词后命名实体识别, 字符位置对应原文本
+func NerTextEntities(txt string) []ner.Entity {
+	words := Seg(txt)
+	entities := NerEntities(words)
+	ner.Align(txt, words, entities)
+	return entities
+}
+
+// AddGazetteer 添加实体词典, key为实体文本, value为实体类型
+func AddGazetteer(entries map[string]string) {
+	recognizer := NerRecognizer()
+	recognizer.Gazetteer().AddEntries(entries)
+}
+
+// LoadGazetteer 加载实体词典, 每行格式: 实体文本\t实体类型
+func LoadGazetteer(r io.Reader) error {
+	recognizer := NerRecognizer()
+	return recognizer.Gazetteer().Load(r)
+}
+
+// DelGazetteer 删除实体词典中的实体
+func DelGazetteer(texts []string) {
+	recognizer := NerRecognizer()
+	for _, txt := range texts {
+		recognizer.Gazetteer().Del(txt)
+	}
+}
+
+// SetNerMergePolicy 设置实体词典与模型结果的合并策略
+func SetNerMergePolicy(policy ner.MergePolicy) {
+	recognizer := NerRecognizer()
+	recognizer.SetPolicy(policy)
+}
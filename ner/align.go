@@ -0,0 +1,34 @@
+package ner
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Align 将实体的字符位置由tokens拼接文本转换为原文本位置(分词会丢弃空白等字符)
+func Align(txt string, tokens []string, entities []Entity) {
+	var (
+		deltas  = make([]int, len(tokens))
+		bytePos int
+		pos     int
+		offset  int
+	)
+	for idx, token := range tokens {
+		if i := strings.Index(txt[bytePos:], token); i >= 0 {
+			pos += utf8.RuneCountInString(txt[bytePos : bytePos+i])
+			bytePos += i + len(token)
+		}
+		deltas[idx] = pos - offset
+		tokenLen := utf8.RuneCountInString(token)
+		pos += tokenLen
+		offset += tokenLen
+	}
+	for idx := range entities {
+		e := &entities[idx]
+		if e.Start < 0 || e.End > len(tokens) || e.Start >= e.End {
+			continue
+		}
+		e.CharStart += deltas[e.Start]
+		e.CharEnd += deltas[e.End-1]
+	}
+}
@@ -0,0 +1,77 @@
+package ner
+
+import (
+	"strings"
+
+	"github.com/bububa/jiagu/perceptron/model"
+)
+
+// Decode 根据BIO(兼容BIOES)标签组装实体
+// 不合法的标签序列(I-开头、类型不一致等)按新实体开始处理
+func Decode(tokens []string, labels []model.Class) []Entity {
+	var (
+		entities []Entity
+		current  *Entity
+		scores   float64
+		offset   int
+	)
+	closeEntity := func() {
+		if current == nil {
+			return
+		}
+		current.Confidence = scores / float64(current.End-current.Start)
+		entities = append(entities, *current)
+		current = nil
+		scores = 0
+	}
+	for idx, token := range tokens {
+		tokenLen := len([]rune(token))
+		var label model.Class
+		if idx < len(labels) {
+			label = labels[idx]
+		}
+		prefix, typ := parseLabel(label.Label)
+		switch prefix {
+		case "B", "S":
+			closeEntity()
+		case "I", "M", "E":
+			if current != nil && current.Type != typ {
+				closeEntity()
+			}
+		default:
+			closeEntity()
+			offset += tokenLen
+			continue
+		}
+		if current == nil {
+			current = &Entity{
+				Type:      typ,
+				Start:     idx,
+				CharStart: offset,
+			}
+		}
+		current.Text += token
+		current.End = idx + 1
+		offset += tokenLen
+		current.CharEnd = offset
+		scores += label.Value
+		if prefix == "E" || prefix == "S" {
+			closeEntity()
+		}
+	}
+	closeEntity()
+	return entities
+}
+
+// parseLabel 拆分标签为前缀和实体类型, 非实体返回空前缀
+func parseLabel(label string) (string, string) {
+	if len(label) < 3 || label[1] != '-' {
+		return "", ""
+	}
+	prefix := strings.ToUpper(label[0:1])
+	switch prefix {
+	case "B", "I", "M", "E", "S":
+		return prefix, label[2:]
+	}
+	return "", ""
+}
@@ -0,0 +1,2 @@
+// Package ner 命名实体识别结果处理
+package ner
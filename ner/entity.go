@@ -0,0 +1,16 @@
+package ner
+
+// Entity 命名实体
+type Entity struct {
+	Text       string  `json:"text,omitempty"`
+	Type       string  `json:"type,omitempty"`
+	Start      int     `json:"start"`      // 起始token位置
+	End        int     `json:"end"`        // 结束token位置(不包含)
+	CharStart  int     `json:"char_start"` // 起始字符位置
+	CharEnd    int     `json:"char_end"`   // 结束字符位置(不包含)
+	Confidence float64 `json:"confidence"` // 各token置信度平均值
+}
+
+func (e Entity) String() string {
+	return e.Text + "/" + e.Type
+}
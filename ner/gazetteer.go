@@ -0,0 +1,151 @@
+package ner
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+type trieNode struct {
+	children map[rune]*trieNode
+	typ      string
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{
+		children: make(map[rune]*trieNode),
+	}
+}
+
+// Gazetteer 实体词典, 使用字典树做最长匹配
+type Gazetteer struct {
+	root   *trieNode
+	total  int
+	locker *sync.RWMutex
+}
+
+// NewGazetteer 新建Gazetteer
+func NewGazetteer() *Gazetteer {
+	return &Gazetteer{
+		root:   newTrieNode(),
+		locker: new(sync.RWMutex),
+	}
+}
+
+// Add 添加实体, typ为实体类型(PER/LOC/ORG...)
+func (g *Gazetteer) Add(text string, typ string) {
+	text = strings.TrimSpace(text)
+	if text == "" || typ == "" {
+		return
+	}
+	g.locker.Lock()
+	defer g.locker.Unlock()
+	node := g.root
+	for _, r := range text {
+		child, found := node.children[r]
+		if !found {
+			child = newTrieNode()
+			node.children[r] = child
+		}
+		node = child
+	}
+	if node.typ == "" {
+		g.total++
+	}
+	node.typ = typ
+}
+
+// AddEntries 批量添加实体, key为实体文本, value为实体类型
+func (g *Gazetteer) AddEntries(entries map[string]string) {
+	for text, typ := range entries {
+		g.Add(text, typ)
+	}
+}
+
+// Del 删除实体
+func (g *Gazetteer) Del(text string) {
+	g.locker.Lock()
+	defer g.locker.Unlock()
+	node := g.root
+	for _, r := range text {
+		child, found := node.children[r]
+		if !found {
+			return
+		}
+		node = child
+	}
+	if node.typ != "" {
+		node.typ = ""
+		g.total--
+	}
+}
+
+// Load 从io.Reader加载实体, 每行格式: 实体文本\t实体类型
+func (g *Gazetteer) Load(r io.Reader) error {
+	buf := bufio.NewReader(r)
+	for {
+		line, err := buf.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if fields := strings.Split(strings.TrimSpace(line), "\t"); len(fields) == 2 {
+			g.Add(fields[0], strings.TrimSpace(fields[1]))
+		}
+		if err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// Total 实体数量
+func (g *Gazetteer) Total() int {
+	g.locker.RLock()
+	defer g.locker.RUnlock()
+	return g.total
+}
+
+// Match 从左到右最长匹配文本中的实体, 返回结果只包含字符位置
+func (g *Gazetteer) Match(txt string) []Entity {
+	g.locker.RLock()
+	defer g.locker.RUnlock()
+	var entities []Entity
+	if g.total == 0 {
+		return entities
+	}
+	runes := []rune(txt)
+	l := len(runes)
+	var i int
+	for i < l {
+		var (
+			node = g.root
+			end  int
+			typ  string
+		)
+		for j := i; j < l; j++ {
+			child, found := node.children[runes[j]]
+			if !found {
+				break
+			}
+			node = child
+			if node.typ != "" {
+				end, typ = j+1, node.typ
+			}
+		}
+		if typ == "" {
+			i++
+			continue
+		}
+		entities = append(entities, Entity{
+			Text:       string(runes[i:end]),
+			Type:       typ,
+			CharStart:  i,
+			CharEnd:    end,
+			Confidence: 1,
+		})
+		i = end
+	}
+	return entities
+}
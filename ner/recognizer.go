@@ -0,0 +1,142 @@
+package ner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bububa/jiagu/perceptron"
+)
+
+// MergePolicy 词典实体与模型实体冲突时的处理策略
+type MergePolicy = string
+
+const (
+	// Gazetteer_MergePolicy 词典实体优先
+	Gazetteer_MergePolicy MergePolicy = "gazetteer"
+	// Model_MergePolicy 模型实体优先
+	Model_MergePolicy MergePolicy = "model"
+	// Longest_MergePolicy 较长的实体优先, 长度相同时词典优先
+	Longest_MergePolicy MergePolicy = "longest"
+)
+
+// Recognizer 结合实体词典与感知机模型的命名实体识别
+type Recognizer struct {
+	model     *perceptron.Perceptron
+	gazetteer *Gazetteer
+	policy    MergePolicy
+}
+
+// NewRecognizer 新建Recognizer
+func NewRecognizer(model *perceptron.Perceptron, gazetteer *Gazetteer) *Recognizer {
+	if gazetteer == nil {
+		gazetteer = NewGazetteer()
+	}
+	return &Recognizer{
+		model:     model,
+		gazetteer: gazetteer,
+		policy:    Gazetteer_MergePolicy,
+	}
+}
+
+// SetPolicy 设置合并策略
+func (r *Recognizer) SetPolicy(policy MergePolicy) {
+	r.policy = policy
+}
+
+// Gazetteer 获取实体词典
+func (r *Recognizer) Gazetteer() *Gazetteer {
+	return r.gazetteer
+}
+
+// Entities 识别tokens中的实体
+func (r *Recognizer) Entities(tokens []string) []Entity {
+	var modelEntities []Entity
+	if r.model != nil {
+		modelEntities = Decode(tokens, r.model.PredictProb(tokens))
+	}
+	gazEntities := r.gazetteer.Match(strings.Join(tokens, ""))
+	if len(gazEntities) == 0 {
+		return modelEntities
+	}
+	alignTokens(tokens, gazEntities)
+	return Merge(modelEntities, gazEntities, r.policy)
+}
+
+// alignTokens 根据字符位置计算实体的token位置
+func alignTokens(tokens []string, entities []Entity) {
+	var (
+		offset int
+		starts = make([]int, len(tokens))
+	)
+	for idx, token := range tokens {
+		starts[idx] = offset
+		offset += len([]rune(token))
+	}
+	tokenAt := func(charIdx int) int {
+		return sort.Search(len(starts), func(i int) bool {
+			return starts[i] > charIdx
+		}) - 1
+	}
+	for idx := range entities {
+		entities[idx].Start = tokenAt(entities[idx].CharStart)
+		entities[idx].End = tokenAt(entities[idx].CharEnd-1) + 1
+	}
+}
+
+// Merge 按策略合并模型实体与词典实体, 去除位置重叠的实体
+func Merge(modelEntities []Entity, gazEntities []Entity, policy MergePolicy) []Entity {
+	type candidate struct {
+		entity    Entity
+		gazetteer bool
+	}
+	candidates := make([]candidate, 0, len(modelEntities)+len(gazEntities))
+	for _, e := range gazEntities {
+		candidates = append(candidates, candidate{entity: e, gazetteer: true})
+	}
+	for _, e := range modelEntities {
+		candidates = append(candidates, candidate{entity: e})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		lenA := a.entity.CharEnd - a.entity.CharStart
+		lenB := b.entity.CharEnd - b.entity.CharStart
+		switch policy {
+		case Model_MergePolicy:
+			if a.gazetteer != b.gazetteer {
+				return !a.gazetteer
+			}
+		case Longest_MergePolicy:
+			if lenA != lenB {
+				return lenA > lenB
+			}
+			if a.gazetteer != b.gazetteer {
+				return a.gazetteer
+			}
+		default:
+			if a.gazetteer != b.gazetteer {
+				return a.gazetteer
+			}
+		}
+		if lenA != lenB {
+			return lenA > lenB
+		}
+		return a.entity.CharStart < b.entity.CharStart
+	})
+	var ret []Entity
+	for _, c := range candidates {
+		var overlapped bool
+		for _, e := range ret {
+			if c.entity.CharStart < e.CharEnd && e.CharStart < c.entity.CharEnd {
+				overlapped = true
+				break
+			}
+		}
+		if !overlapped {
+			ret = append(ret, c.entity)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].CharStart < ret[j].CharStart
+	})
+	return ret
+}
@@ -2,6 +2,9 @@ package jiagu
 
 import (
 	"testing"
+
+	"github.com/bububa/jiagu/ner"
+	"github.com/bububa/jiagu/perceptron/model"
 )
 
 // TestNer 测试命名实体识别
@@ -26,3 +29,91 @@ func TestNer(t *testing.T) {
 		}
 	}
 }
+
+// TestNerEntities 测试命名实体片段
+func TestNerEntities(t *testing.T) {
+	txt := "厦门明天会不会下雨"
+	words := Seg(txt)
+	entities := NerEntities(words)
+	if len(entities) != 1 {
+		t.Errorf("result: %+v, expect: 1 entity\n", entities)
+		return
+	}
+	e := entities[0]
+	if e.Text != "厦门" || e.Type != "LOC" || e.Start != 0 || e.End != 1 || e.CharStart != 0 || e.CharEnd != 2 {
+		t.Errorf("result: %+v\n", e)
+	}
+	if e.Confidence <= 0 || e.Confidence > 1 {
+		t.Errorf("invalid confidence: %f\n", e.Confidence)
+	}
+}
+
+// TestNerDecode 测试不合法标签序列
+func TestNerDecode(t *testing.T) {
+	tokens := []string{"在", "北", "京", "见", "张", "三", "丰"}
+	labels := []model.Class{
+		{Label: "O", Value: 1},
+		{Label: "I-LOC", Value: 0.8},
+		{Label: "I-LOC", Value: 0.6},
+		{Label: "O", Value: 1},
+		{Label: "B-PER", Value: 1},
+		{Label: "I-ORG", Value: 0.5},
+		{Label: "I-ORG", Value: 0.5},
+	}
+	expects := []string{"北京/LOC", "张/PER", "三丰/ORG"}
+	entities := ner.Decode(tokens, labels)
+	if len(entities) != len(expects) {
+		t.Errorf("result: %+v, expect: %+v\n", entities, expects)
+		return
+	}
+	for idx, e := range entities {
+		if e.String() != expects[idx] {
+			t.Errorf("result: %+v, expect: %+v\n", entities, expects)
+			break
+		}
+	}
+	if entities[0].CharStart != 1 || entities[0].CharEnd != 3 || entities[0].Confidence != 0.7 {
+		t.Errorf("result: %+v\n", entities[0])
+	}
+}
+
+// TestNerGazetteer 测试实体词典
+func TestNerGazetteer(t *testing.T) {
+	AddGazetteer(map[string]string{
+		"甲骨科技": "ORG",
+		"甲骨":   "PRODUCT",
+	})
+	defer DelGazetteer([]string{"甲骨科技", "甲骨"})
+	txt := "厦门甲骨科技明天发布"
+	entities := NerCharEntities(txt)
+	var found bool
+	for _, e := range entities {
+		if e.Text == "甲骨科技" && e.Type == "ORG" && e.CharStart == 2 && e.CharEnd == 6 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("result: %+v, expect: 甲骨科技/ORG\n", entities)
+	}
+}
+
+// TestNerMerge 测试实体合并策略
+func TestNerMerge(t *testing.T) {
+	modelEntities := []ner.Entity{
+		{Text: "北京大学", Type: "ORG", CharStart: 0, CharEnd: 4},
+	}
+	gazEntities := []ner.Entity{
+		{Text: "北京", Type: "LOC", CharStart: 0, CharEnd: 2},
+	}
+	tests := map[ner.MergePolicy]string{
+		ner.Gazetteer_MergePolicy: "北京/LOC",
+		ner.Model_MergePolicy:     "北京大学/ORG",
+		ner.Longest_MergePolicy:   "北京大学/ORG",
+	}
+	for policy, expect := range tests {
+		entities := ner.Merge(modelEntities, gazEntities, policy)
+		if len(entities) != 1 || entities[0].String() != expect {
+			t.Errorf("policy: %s, result: %+v, expect: %s\n", policy, entities, expect)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package perceptron
 import (
 	"encoding/gob"
 	"io"
+	"math"
 	"sort"
 
 	"github.com/shopspring/decimal"
@@ -60,6 +61,35 @@ func (p *AveragedPerceptron) Len() int {
 
 // Predict Dot-product the features and current weights and return the best label.
 func (p *AveragedPerceptron) Predict(features []model.Feature) model.Class {
+	scoreSlice := p.scores(features)
+	if len(scoreSlice) == 0 {
+		return model.Class{}
+	}
+	sort.Sort(sort.Reverse(scoreSlice))
+	return scoreSlice[0]
+}
+
+// PredictProb return the best label with its softmax probability over all classes.
+func (p *AveragedPerceptron) PredictProb(features []model.Feature) model.Class {
+	scoreSlice := p.scores(features)
+	if len(scoreSlice) == 0 {
+		return model.Class{}
+	}
+	sort.Sort(sort.Reverse(scoreSlice))
+	best := scoreSlice[0]
+	// 未出现在scores中的class得分为0
+	var sum float64
+	for _, kv := range scoreSlice {
+		sum += math.Exp(kv.Value - best.Value)
+	}
+	sum += float64(p.Len()-len(scoreSlice)) * math.Exp(-best.Value)
+	return model.Class{
+		Label: best.Label,
+		Value: 1 / sum,
+	}
+}
+
+func (p *AveragedPerceptron) scores(features []model.Feature) model.KVSlice {
 	scores := make(map[string]float64, p.Len())
 	for _, feature := range features {
 		if feature.IsZero() {
@@ -73,16 +103,11 @@ func (p *AveragedPerceptron) Predict(features []model.Feature) model.Class {
 			scores[kv.Label] += kv.Value * feature.Value
 		}
 	}
-	totalScores := len(scores)
-	if totalScores == 0 {
-		return model.Class{}
-	}
-	scoreSlice := model.NewKVSlice(totalScores)
+	scoreSlice := model.NewKVSlice(len(scores))
 	for label, value := range scores {
 		scoreSlice = append(scoreSlice, model.KV{Label: label, Value: value})
 	}
-	sort.Sort(sort.Reverse(scoreSlice))
-	return scoreSlice[0]
+	return scoreSlice
 }
 
 // Update update the feature weights.
@@ -65,6 +65,15 @@ func NewFromModelFile(loc string) (*Perceptron, error) {
 
 // Predect 预测分类
 func (p *Perceptron) Predict(words []string) []model.Class {
+	return p.predict(words, p.model.Predict)
+}
+
+// PredictProb 预测分类, Class.Value为该分类的概率
+func (p *Perceptron) PredictProb(words []string) []model.Class {
+	return p.predict(words, p.model.PredictProb)
+}
+
+func (p *Perceptron) predict(words []string, predictor func([]model.Feature) model.Class) []model.Class {
 	var classes []model.Class
 	cap := len(p.starts) + len(words) + len(p.ends)
 	context := make([]string, 0, cap)
@@ -73,7 +82,7 @@ func (p *Perceptron) Predict(words []string) []model.Class {
 	prev, prev2 := p.starts[0], p.starts[1]
 	for idx, word := range words {
 		features := p.getFeatures(idx, word, context, prev, prev2)
-		class := p.model.Predict(features)
+		class := predictor(features)
 		classes = append(classes, class)
 		prev2 = prev
 		prev = class.Label
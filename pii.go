@@ -0,0 +1,28 @@
+package jiagu
+
+import (
+	"github.com/bububa/jiagu/pii"
+)
+
+var piiDetector *pii.Detector
+
+// PIIDetector get piiDetector singleton
+func PIIDetector() *pii.Detector {
+	if piiDetector == nil {
+		piiDetector = pii.New()
+		piiDetector.SetEntityFunc(NerTextEntities)
+	}
+	return piiDetector
+}
+
+// DetectPII 检测个人敏感信息
+func DetectPII(txt string) []pii.Match {
+	detector := PIIDetector()
+	return detector.Detect(txt)
+}
+
+// RedactPII 个人敏感信息脱敏, mode支持检测、遮盖及假名替换
+func RedactPII(txt string, mode pii.Mode) (string, []pii.Match) {
+	detector := PIIDetector()
+	return detector.Redact(txt, mode)
+}
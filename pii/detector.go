@@ -0,0 +1,228 @@
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bububa/jiagu/ner"
+)
+
+// Mode 处理模式
+type Mode = string
+
+const (
+	// Detect_Mode 仅检测, 不修改文本
+	Detect_Mode Mode = "detect"
+	// Mask_Mode 使用掩码字符遮盖
+	Mask_Mode Mode = "mask"
+	// Pseudonymize_Mode 替换为稳定的假名标识
+	Pseudonymize_Mode Mode = "pseudonymize"
+)
+
+// EntityFunc 命名实体识别方法, 实体字符位置需对应原文本
+type EntityFunc = func(txt string) []ner.Entity
+
+// Match 检测结果
+type Match struct {
+	Kind        Kind   `json:"kind,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Start       int    `json:"start"`                 // 原文本起始字符位置
+	End         int    `json:"end"`                   // 原文本结束字符位置(不包含)
+	Replacement string `json:"replacement,omitempty"` // 脱敏后文本
+}
+
+// Detector 敏感信息检测
+type Detector struct {
+	rules       []Rule
+	entityFunc  EntityFunc
+	entityKinds map[string]Kind
+	maskChar    rune
+	salt        string
+}
+
+// New 新建Detector, 假名密钥默认随机生成, 需跨进程保持假名稳定时使用SetSalt设置
+func New() *Detector {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		panic(err)
+	}
+	return &Detector{
+		rules: DefaultRules(),
+		entityKinds: map[string]Kind{
+			"PER": Person_Kind,
+			"LOC": Location_Kind,
+		},
+		maskChar: '*',
+		salt:     string(salt),
+	}
+}
+
+// AddRule 添加检测规则
+func (d *Detector) AddRule(rule Rule) {
+	d.rules = append(d.rules, rule)
+}
+
+// SetEntityFunc 设置命名实体识别方法
+func (d *Detector) SetEntityFunc(fn EntityFunc) {
+	d.entityFunc = fn
+}
+
+// SetEntityKinds 设置需要检测的实体类型, key为NER实体类型
+func (d *Detector) SetEntityKinds(kinds map[string]Kind) {
+	d.entityKinds = kinds
+}
+
+// SetMaskChar 设置掩码字符
+func (d *Detector) SetMaskChar(ch rune) {
+	d.maskChar = ch
+}
+
+// SetSalt 设置假名生成的密钥, 应使用足够长的随机值, 否则手机号等取值空间较小的信息可被穷举还原
+func (d *Detector) SetSalt(salt string) {
+	d.salt = salt
+}
+
+// Detect 检测敏感信息
+func (d *Detector) Detect(txt string) []Match {
+	var (
+		matches []Match
+		spans   [][2]int
+	)
+	overlapped := func(start int, end int) bool {
+		for _, span := range spans {
+			if start < span[1] && span[0] < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, rule := range d.rules {
+		for _, loc := range rule.find(txt) {
+			if overlapped(loc[0], loc[1]) {
+				continue
+			}
+			spans = append(spans, loc)
+			start := utf8.RuneCountInString(txt[:loc[0]])
+			matches = append(matches, Match{
+				Kind:  rule.Kind,
+				Text:  txt[loc[0]:loc[1]],
+				Start: start,
+				End:   start + utf8.RuneCountInString(txt[loc[0]:loc[1]]),
+			})
+		}
+	}
+	if d.entityFunc != nil {
+		runeIdx := byteIndexes(txt)
+		for _, e := range d.entityFunc(txt) {
+			kind, found := d.entityKinds[e.Type]
+			if !found || e.CharStart < 0 || e.CharEnd >= len(runeIdx) || e.CharStart >= e.CharEnd {
+				continue
+			}
+			loc := [2]int{runeIdx[e.CharStart], runeIdx[e.CharEnd]}
+			if overlapped(loc[0], loc[1]) {
+				continue
+			}
+			spans = append(spans, loc)
+			matches = append(matches, Match{
+				Kind:  kind,
+				Text:  txt[loc[0]:loc[1]],
+				Start: e.CharStart,
+				End:   e.CharEnd,
+			})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return matches
+}
+
+// Redact 按模式处理文本, 返回处理后文本及检测结果
+func (d *Detector) Redact(txt string, mode Mode) (string, []Match) {
+	matches := d.Detect(txt)
+	if mode == Detect_Mode || len(matches) == 0 {
+		return txt, matches
+	}
+	var (
+		buf     strings.Builder
+		runes   = []rune(txt)
+		lastIdx int
+	)
+	for idx, m := range matches {
+		if mode == Pseudonymize_Mode {
+			m.Replacement = d.pseudonym(m)
+		} else {
+			m.Replacement = d.mask(m)
+		}
+		matches[idx] = m
+		buf.WriteString(string(runes[lastIdx:m.Start]))
+		buf.WriteString(m.Replacement)
+		lastIdx = m.End
+	}
+	buf.WriteString(string(runes[lastIdx:]))
+	return buf.String(), matches
+}
+
+// Mask 遮盖敏感信息
+func (d *Detector) Mask(txt string) (string, []Match) {
+	return d.Redact(txt, Mask_Mode)
+}
+
+// Pseudonymize 替换敏感信息为假名
+func (d *Detector) Pseudonymize(txt string) (string, []Match) {
+	return d.Redact(txt, Pseudonymize_Mode)
+}
+
+// mask 保留部分首尾字符, 其余使用掩码字符
+func (d *Detector) mask(m Match) string {
+	runes := []rune(m.Text)
+	var head, tail int
+	switch m.Kind {
+	case Mobile_Kind:
+		head, tail = len(runes)-8, 4
+	case IDCard_Kind:
+		head, tail = 6, 4
+	case BankCard_Kind:
+		head, tail = 4, 4
+	case Email_Kind:
+		if at := strings.IndexRune(m.Text, '@'); at > 0 {
+			head, tail = 1, len(runes)-utf8.RuneCountInString(m.Text[:at])
+		}
+	case LicensePlate_Kind:
+		head = 2
+	case Person_Kind:
+		head = 1
+	}
+	if head+tail >= len(runes) {
+		head, tail = 0, 0
+	}
+	for idx := head; idx < len(runes)-tail; idx++ {
+		if runes[idx] == ' ' || runes[idx] == '-' {
+			continue
+		}
+		runes[idx] = d.maskChar
+	}
+	return string(runes)
+}
+
+// pseudonym 根据密钥生成稳定的假名, 相同内容得到相同假名
+func (d *Detector) pseudonym(m Match) string {
+	mac := hmac.New(sha256.New, []byte(d.salt))
+	mac.Write([]byte(m.Kind))
+	mac.Write([]byte(m.Text))
+	return "<" + m.Kind + "_" + hex.EncodeToString(mac.Sum(nil))[:8] + ">"
+}
+
+// byteIndexes 字符位置到字节位置的映射, 包含结尾位置
+func byteIndexes(txt string) []int {
+	ret := make([]int, 0, len(txt)+1)
+	for idx := range txt {
+		ret = append(ret, idx)
+	}
+	return append(ret, len(txt))
+}
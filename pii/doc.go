@@ -0,0 +1,2 @@
+// Package pii 个人敏感信息检测及脱敏
+package pii
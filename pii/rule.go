@@ -0,0 +1,119 @@
+package pii
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind 敏感信息类型
+type Kind = string
+
+const (
+	// Mobile_Kind 手机号码
+	Mobile_Kind Kind = "MOBILE"
+	// IDCard_Kind 居民身份证号码
+	IDCard_Kind Kind = "ID_CARD"
+	// BankCard_Kind 银行卡号
+	BankCard_Kind Kind = "BANK_CARD"
+	// Email_Kind 电子邮箱
+	Email_Kind Kind = "EMAIL"
+	// LicensePlate_Kind 车牌号码
+	LicensePlate_Kind Kind = "LICENSE_PLATE"
+	// Address_Kind 地址
+	Address_Kind Kind = "ADDRESS"
+	// Person_Kind 人名
+	Person_Kind Kind = "PERSON"
+	// Location_Kind 地名
+	Location_Kind Kind = "LOCATION"
+)
+
+// Rule 基于正则的检测规则
+type Rule struct {
+	Kind     Kind
+	Pattern  *regexp.Regexp
+	Validate func(string) bool // 可选, 校验匹配结果
+	Boundary bool              // 匹配结果前后不能紧邻字母或数字
+}
+
+var addressStopRunes = "在于住址是到从往：:"
+
+// DefaultRules 默认检测规则, 按优先级排列
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Kind:     Email_Kind,
+			Pattern:  regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+			Boundary: true,
+		},
+		{
+			Kind:     IDCard_Kind,
+			Pattern:  regexp.MustCompile(`[1-9]\d{5}(?:18|19|20)\d{2}\d{7}[\dXx]`),
+			Validate: ValidateIDCard,
+			Boundary: true,
+		},
+		{
+			Kind:     Mobile_Kind,
+			Pattern:  regexp.MustCompile(`(?:\+?86[- ]?)?1[3-9]\d{9}`),
+			Boundary: true,
+		},
+		{
+			Kind:     BankCard_Kind,
+			Pattern:  regexp.MustCompile(`[1-9]\d{3}(?:[ \-]?\d{4}){2,3}(?:[ \-]?\d{1,4})?`),
+			Validate: ValidateLuhn,
+			Boundary: true,
+		},
+		{
+			Kind:     LicensePlate_Kind,
+			Pattern:  regexp.MustCompile(`[京津沪渝冀豫云辽黑湘皖鲁新苏浙赣鄂桂甘晋蒙陕吉闽贵粤青藏川宁琼][A-HJ-NP-Z][·•]?(?:[A-HJ-NP-Z0-9]{5,6}|[A-HJ-NP-Z0-9]{4}[挂学警港澳])`),
+			Boundary: true,
+		},
+		{
+			Kind:    Address_Kind,
+			Pattern: regexp.MustCompile(`(?:\p{Han}{2,9}?(?:省|自治区|特别行政区))?(?:\p{Han}{1,9}?(?:市|自治州|地区|盟))?(?:\p{Han}{1,9}?(?:区|县|旗))?(?:\p{Han}{1,9}?(?:镇|乡|街道))?[\p{Han}0-9]{1,12}?(?:路|街|道|巷|弄|胡同|村)[0-9一二三四五六七八九十百零]+(?:号|弄)(?:[0-9A-Za-z一二三四五六七八九十]+(?:栋|幢|号楼|单元|室|层|楼))*`),
+		},
+	}
+}
+
+// find 查找规则匹配结果, 返回字节位置
+func (r Rule) find(txt string) [][2]int {
+	var ret [][2]int
+	for _, loc := range r.Pattern.FindAllStringIndex(txt, -1) {
+		start, end := loc[0], loc[1]
+		if r.Kind == Address_Kind {
+			start = trimAddress(txt, start, end)
+		}
+		if r.Boundary && !isBoundary(txt, start, end) {
+			continue
+		}
+		if r.Validate != nil && !r.Validate(txt[start:end]) {
+			continue
+		}
+		ret = append(ret, [2]int{start, end})
+	}
+	return ret
+}
+
+func isBoundary(txt string, start int, end int) bool {
+	if start > 0 {
+		if ch, _ := utf8.DecodeLastRuneInString(txt[:start]); ch < unicode.MaxASCII && (unicode.IsLetter(ch) || unicode.IsDigit(ch)) {
+			return false
+		}
+	}
+	for _, ch := range txt[end:] {
+		return !(ch < unicode.MaxASCII && (unicode.IsLetter(ch) || unicode.IsDigit(ch)))
+	}
+	return true
+}
+
+// trimAddress 去掉地址匹配结果前面的"住在"、"位于"等前缀
+func trimAddress(txt string, start int, end int) int {
+	match := txt[start:end]
+	if idx := strings.LastIndexAny(match, addressStopRunes); idx >= 0 {
+		for _, ch := range match[idx:] {
+			return start + idx + len(string(ch))
+		}
+	}
+	return start
+}
@@ -0,0 +1,70 @@
+package pii
+
+import (
+	"strings"
+	"time"
+)
+
+var (
+	idCardWeights   = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardChecksums = "10X98765432"
+)
+
+// ValidateIDCard 校验18位居民身份证号码(出生日期及校验位)
+func ValidateIDCard(id string) bool {
+	id = strings.ToUpper(id)
+	if len(id) != 18 {
+		return false
+	}
+	var sum int
+	for idx := 0; idx < 17; idx++ {
+		ch := id[idx]
+		if ch < '0' || ch > '9' {
+			return false
+		}
+		sum += int(ch-'0') * idCardWeights[idx]
+	}
+	if id[17] != idCardChecksums[sum%11] {
+		return false
+	}
+	year, month, day := atoi(id[6:10]), atoi(id[10:12]), atoi(id[12:14])
+	birthday := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return birthday.Year() == year && int(birthday.Month()) == month && birthday.Day() == day
+}
+
+// ValidateLuhn Luhn算法校验银行卡号
+func ValidateLuhn(number string) bool {
+	var (
+		sum    int
+		digits int
+		double bool
+	)
+	for idx := len(number) - 1; idx >= 0; idx-- {
+		ch := number[idx]
+		if ch == ' ' || ch == '-' {
+			continue
+		}
+		if ch < '0' || ch > '9' {
+			return false
+		}
+		n := int(ch - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+func atoi(s string) int {
+	var n int
+	for _, ch := range s {
+		n = n*10 + int(ch-'0')
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package jiagu
+
+import (
+	"testing"
+
+	"github.com/bububa/jiagu/pii"
+)
+
+// TestRedactPII 测试敏感信息脱敏
+func TestRedactPII(t *testing.T) {
+	txt := "电话13812345678，身份证11010519491231002X，邮箱zhang@example.com"
+	expect := "电话138****5678，身份证110105********002X，邮箱z****@example.com"
+	result, matches := RedactPII(txt, pii.Mask_Mode)
+	if result != expect {
+		t.Errorf("result: %s, expect: %s\n", result, expect)
+	}
+	kinds := []pii.Kind{pii.Mobile_Kind, pii.IDCard_Kind, pii.Email_Kind}
+	if len(matches) != len(kinds) {
+		t.Errorf("result: %+v, expect: %+v\n", matches, kinds)
+		return
+	}
+	for idx, m := range matches {
+		if m.Kind != kinds[idx] {
+			t.Errorf("result: %+v, expect: %+v\n", matches, kinds)
+			break
+		}
+	}
+}
+
+// TestPIIValidator 测试身份证及银行卡校验
+func TestPIIValidator(t *testing.T) {
+	if !pii.ValidateIDCard("11010519491231002X") {
+		t.Error("expect valid id card")
+	}
+	if pii.ValidateIDCard("110105194912310021") {
+		t.Error("expect invalid id card")
+	}
+	if pii.ValidateIDCard("110105194902310026") {
+		t.Error("expect invalid birthday")
+	}
+	if !pii.ValidateLuhn("4111 1111 1111 1111") {
+		t.Error("expect valid bank card")
+	}
+	if pii.ValidateLuhn("4111111111111112") {
+		t.Error("expect invalid bank card")
+	}
+}
+
+// TestPIIPseudonym 测试假名密钥
+func TestPIIPseudonym(t *testing.T) {
+	txt := "电话13812345678"
+	d1, d2 := pii.New(), pii.New()
+	r1, _ := d1.Pseudonymize(txt)
+	r2, _ := d2.Pseudonymize(txt)
+	if r1 == r2 {
+		t.Errorf("expect random default salt, result: %s, %s\n", r1, r2)
+	}
+	d1.SetSalt("secret")
+	d2.SetSalt("secret")
+	r1, _ = d1.Pseudonymize(txt)
+	r2, _ = d2.Pseudonymize(txt)
+	if r1 != r2 || r1 == txt {
+		t.Errorf("expect stable pseudonym, result: %s, %s\n", r1, r2)
+	}
+}
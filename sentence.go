@@ -0,0 +1,10 @@
+package jiagu
+
+import (
+	"github.com/bububa/jiagu/sentence"
+)
+
+// Sentences 分句, 引号及括号内不分句, 返回句子及其在原文中的字符位置
+func Sentences(txt string) []sentence.Sentence {
+	return sentence.Split(txt)
+}
@@ -0,0 +1,2 @@
+// Package sentence 中文分句, 支持自定义分句标点、引号及括号内不分句, 并返回句子在原文中的位置
+package sentence
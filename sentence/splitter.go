@@ -0,0 +1,213 @@
+package sentence
+
+import "unicode"
+
+const (
+	// DEFAULT_DELIMITERS 默认分句标点, 换行总是分句
+	DEFAULT_DELIMITERS = "。！？!?；;…\n"
+	// DEFAULT_CLOSINGS 默认句末标点后归属前一句的右引号及右括号
+	DEFAULT_CLOSINGS = "”’」』）)】》\"'"
+)
+
+// DefaultPairs 默认成对的引号及括号, 其中的分句标点不分句
+var DefaultPairs = map[rune]rune{
+	'“': '”',
+	'‘': '’',
+	'「': '」',
+	'『': '』',
+	'（': '）',
+	'(': ')',
+	'【': '】',
+	'《': '》',
+	'"': '"',
+}
+
+// Sentence 句子及其在原文中的字符位置
+type Sentence struct {
+	Text  string `json:"text,omitempty"`
+	Start int    `json:"start"`
+	End   int    `json:"end"` // 不包含
+}
+
+// Splitter 分句器
+type Splitter struct {
+	delimiters  map[rune]struct{}
+	closings    map[rune]struct{}
+	pairs       map[rune]rune
+	quoteAware  bool
+	asciiPeriod bool
+}
+
+// NewSplitter 新建Splitter, 使用默认分句标点并开启引号及括号识别
+func NewSplitter() *Splitter {
+	s := &Splitter{
+		quoteAware:  true,
+		asciiPeriod: true,
+	}
+	s.SetDelimiters(DEFAULT_DELIMITERS)
+	s.SetClosings(DEFAULT_CLOSINGS)
+	s.SetPairs(DefaultPairs)
+	return s
+}
+
+// SetDelimiters 设置分句标点
+func (s *Splitter) SetDelimiters(delimiters string) {
+	s.delimiters = runeSet(delimiters)
+}
+
+// SetClosings 设置句末标点后归属前一句的右引号及右括号
+func (s *Splitter) SetClosings(closings string) {
+	s.closings = runeSet(closings)
+}
+
+// SetPairs 设置成对的引号及括号, 左右相同的引号交替匹配
+func (s *Splitter) SetPairs(pairs map[rune]rune) {
+	s.pairs = make(map[rune]rune, len(pairs))
+	for opening, closing := range pairs {
+		s.pairs[opening] = closing
+	}
+}
+
+// SetQuoteAware 设置引号及括号内是否不分句
+func (s *Splitter) SetQuoteAware(quoteAware bool) {
+	s.quoteAware = quoteAware
+}
+
+// SetASCIIPeriod 设置英文句号后接空白或位于末尾时是否分句
+func (s *Splitter) SetASCIIPeriod(asciiPeriod bool) {
+	s.asciiPeriod = asciiPeriod
+}
+
+// Split 分句, 句子去除首尾空白, 位置为原文中的rune位置;
+// 引号或括号到换行或末尾仍未闭合时, 未闭合的部分不识别引号及括号重新分句
+func (s *Splitter) Split(txt string) []Sentence {
+	var (
+		ret   []Sentence
+		runes = []rune(txt)
+		start int
+	)
+	_, newline := s.delimiters['\n']
+	for idx, r := range runes {
+		if r == '\n' && newline {
+			ret = s.splitParagraph(ret, runes, start, idx+1)
+			start = idx + 1
+		}
+	}
+	return s.splitParagraph(ret, runes, start, len(runes))
+}
+
+// splitParagraph 分句runes[from:to]并追加到ret
+func (s *Splitter) splitParagraph(ret []Sentence, runes []rune, from int, to int) []Sentence {
+	ret, open := s.split(ret, runes, from, to, s.quoteAware)
+	if open < to {
+		ret, _ = s.split(ret, runes, open, to, false)
+	}
+	return ret
+}
+
+// split 分句runes[from:to]并追加到ret, 返回引号或括号未闭合部分的起始位置, 均已闭合时为to
+func (s *Splitter) split(ret []Sentence, runes []rune, from int, to int, quoteAware bool) ([]Sentence, int) {
+	var (
+		stack   []rune // 待匹配的右引号及右括号
+		start   = from
+		pending bool // 已出现句末标点, 等待引号或括号闭合
+	)
+	emit := func(end int) {
+		from, to := start, end
+		for from < to && unicode.IsSpace(runes[from]) {
+			from++
+		}
+		for to > from && unicode.IsSpace(runes[to-1]) {
+			to--
+		}
+		if from < to {
+			ret = append(ret, Sentence{
+				Text:  string(runes[from:to]),
+				Start: from,
+				End:   to,
+			})
+		}
+		start = end
+		pending = false
+	}
+	for idx := from; idx < to; idx++ {
+		if quoteAware {
+			stack = s.match(stack, runes[idx])
+		}
+		if s.isDelimiter(runes, idx) {
+			pending = true
+		}
+		if !pending || len(stack) > 0 {
+			continue
+		}
+		if next := idx + 1; next < to && runes[next] != '\n' && (s.isClosing(runes[next]) || s.isDelimiter(runes, next)) {
+			continue
+		}
+		emit(idx + 1)
+	}
+	if len(stack) > 0 {
+		return ret, start
+	}
+	emit(to)
+	return ret, to
+}
+
+// match 匹配引号及括号, 右引号或右括号与栈顶不匹配时弹出到与其匹配的位置, 栈中没有与其匹配的则忽略
+func (s *Splitter) match(stack []rune, r rune) []rune {
+	for idx := len(stack) - 1; idx >= 0; idx-- {
+		if stack[idx] == r {
+			return stack[:idx]
+		}
+	}
+	if closing, found := s.pairs[r]; found {
+		return append(stack, closing)
+	}
+	return stack
+}
+
+// Texts 分句, 只返回句子文本
+func (s *Splitter) Texts(txt string) []string {
+	sentences := s.Split(txt)
+	ret := make([]string, 0, len(sentences))
+	for _, sent := range sentences {
+		ret = append(ret, sent.Text)
+	}
+	return ret
+}
+
+func (s *Splitter) isClosing(r rune) bool {
+	_, found := s.closings[r]
+	return found
+}
+
+func (s *Splitter) isDelimiter(runes []rune, idx int) bool {
+	r := runes[idx]
+	if _, found := s.delimiters[r]; found {
+		return true
+	}
+	if r != '.' || !s.asciiPeriod {
+		return false
+	}
+	// 英文句号后需为空白或末尾, 避免拆分小数及网址
+	return idx+1 == len(runes) || unicode.IsSpace(runes[idx+1])
+}
+
+func runeSet(str string) map[rune]struct{} {
+	set := make(map[rune]struct{}, len(str))
+	for _, r := range str {
+		set[r] = struct{}{}
+	}
+	return set
+}
+
+var defaultSplitter = NewSplitter()
+
+// Split 使用默认配置分句
+func Split(txt string) []Sentence {
+	return defaultSplitter.Split(txt)
+}
+
+// Texts 使用默认配置分句, 只返回句子文本
+func Texts(txt string) []string {
+	return defaultSplitter.Texts(txt)
+}
@@ -0,0 +1,83 @@
+package jiagu
+
+import (
+	"testing"
+
+	"github.com/bububa/jiagu/sentence"
+)
+
+// TestSentences 测试分句
+func TestSentences(t *testing.T) {
+	txt := "他说：“今天下雨。别出门！”我们回家吧！\n 好的"
+	expects := []sentence.Sentence{
+		{Text: "他说：“今天下雨。别出门！”", Start: 0, End: 14},
+		{Text: "我们回家吧！", Start: 14, End: 20},
+		{Text: "好的", Start: 22, End: 24},
+	}
+	sentences := Sentences(txt)
+	if len(sentences) != len(expects) {
+		t.Errorf("result: %+v, expect: %+v\n", sentences, expects)
+		return
+	}
+	for idx, sent := range sentences {
+		if sent != expects[idx] {
+			t.Errorf("result: %+v, expect: %+v\n", sentences, expects)
+			break
+		}
+	}
+}
+
+// TestSentenceSplitter 测试自定义分句标点
+func TestSentenceSplitter(t *testing.T) {
+	splitter := sentence.NewSplitter()
+	splitter.SetDelimiters("。，")
+	splitter.SetQuoteAware(false)
+	txt := "圆周率约为3.14，“对吗？”不知道。"
+	expects := []string{"圆周率约为3.14，", "“对吗？”不知道。"}
+	sentences := splitter.Texts(txt)
+	if len(sentences) != len(expects) {
+		t.Errorf("result: %+v, expect: %+v\n", sentences, expects)
+		return
+	}
+	for idx, sent := range sentences {
+		if sent != expects[idx] {
+			t.Errorf("result: %+v, expect: %+v\n", sentences, expects)
+			break
+		}
+	}
+	// 引号及括号不成对时不影响之后的分句
+	cases := []struct {
+		txt     string
+		expects []string
+	}{
+		{
+			txt:     "他说“你好。今天天气好。明天下雨。后天晴。",
+			expects: []string{"他说“你好。", "今天天气好。", "明天下雨。", "后天晴。"},
+		},
+		{
+			txt:     "价格（含税。今天到货。明天发货。",
+			expects: []string{"价格（含税。", "今天到货。", "明天发货。"},
+		},
+		{
+			txt:     "A“B（C”D。E。F。",
+			expects: []string{"A“B（C”D。", "E。", "F。"},
+		},
+		{
+			txt:     "他说“你好。\n今天“天气”好。明天下雨。",
+			expects: []string{"他说“你好。", "今天“天气”好。", "明天下雨。"},
+		},
+	}
+	for _, c := range cases {
+		sentences := sentence.Texts(c.txt)
+		if len(sentences) != len(c.expects) {
+			t.Errorf("result: %+v, expect: %+v\n", sentences, c.expects)
+			continue
+		}
+		for idx, sent := range sentences {
+			if sent != c.expects[idx] {
+				t.Errorf("result: %+v, expect: %+v\n", sentences, c.expects)
+				break
+			}
+		}
+	}
+}
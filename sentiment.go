@@ -3,11 +3,17 @@ package jiagu
 import (
 	"compress/gzip"
 	"fmt"
+	"io"
 
 	"github.com/bububa/jiagu/classify/bayes"
+	"github.com/bububa/jiagu/sentiment"
 )
 
-var sentimentModel *bayes.Bayes
+var (
+	sentimentModel    *bayes.Bayes
+	sentimentAnalyzer *sentiment.Analyzer
+	aspectExtractor   *sentiment.AspectExtractor
+)
 
 // SentimentInstance get sentimentModel singleton
 func SentimentInstance() *bayes.Bayes {
@@ -35,3 +41,84 @@ func Sentiment(words []string) (string, float64) {
 	SentimentInstance()
 	return sentimentModel.Classify(words)
 }
+
+// SentimentProba 情感分析, 返回全部分类的概率
+func SentimentProba(words []string) []bayes.Prob {
+	SentimentInstance()
+	return sentimentModel.ClassifyProba(words)
+}
+
+// LoadSentimentModel 加载自定义情感分析模型(gzip压缩的gob格式)
+func LoadSentimentModel(r io.Reader) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	model, err := bayes.NewFromReader(gr)
+	if err != nil {
+		return err
+	}
+	sentimentModel = model
+	return nil
+}
+
+// SentimentAnalyzer get sentimentAnalyzer singleton
+func SentimentAnalyzer() *sentiment.Analyzer {
+	if sentimentAnalyzer == nil {
+		lexicon := sentiment.NewLexicon()
+		loaders := map[string]func(io.Reader) error{
+			SENTIMENT_DICT: lexicon.LoadSentiments,
+			NEGATION_DICT:  lexicon.LoadNegations,
+			DEGREE_DICT:    lexicon.LoadDegrees,
+		}
+		for dict, loader := range loaders {
+			fd, err := dictFS.Open(fmt.Sprintf("dict/%s", dict))
+			if err != nil {
+				panic(err)
+			}
+			err = loader(fd)
+			fd.Close()
+			if err != nil {
+				panic(err)
+			}
+		}
+		sentimentAnalyzer = sentiment.NewAnalyzer(lexicon)
+	}
+	return sentimentAnalyzer
+}
+
+// SentimentScore 基于情感词典的情感分析, 处理否定词及程度副词
+func SentimentScore(words []string) sentiment.Result {
+	analyzer := SentimentAnalyzer()
+	return analyzer.Analyze(words)
+}
+
+// AspectExtractor get aspectExtractor singleton
+func AspectExtractor() *sentiment.AspectExtractor {
+	if aspectExtractor == nil {
+		aspectExtractor = sentiment.NewAspectExtractor(SentimentAnalyzer())
+		aspectExtractor.SetBayes(SentimentInstance())
+	}
+	return aspectExtractor
+}
+
+// Aspects 评价对象-评价词抽取, 基于分词及词性标注
+func Aspects(txt string) []sentiment.AspectOpinion {
+	words := Seg(txt)
+	labels := Pos(words)
+	tags := make([]string, len(labels))
+	for idx, label := range labels {
+		tags[idx] = label.Label
+	}
+	return AspectExtractor().Extract(words, tags)
+}
+
+// AspectSummary 对评论语料按评价对象汇总情感倾向
+func AspectSummary(reviews []string) []sentiment.AspectSummary {
+	aggregator := sentiment.NewAspectAggregator()
+	for _, review := range reviews {
+		aggregator.Add(Aspects(review))
+	}
+	return aggregator.Summaries()
+}
@@ -0,0 +1,179 @@
+package sentiment
+
+import (
+	"math"
+	"strings"
+
+	"github.com/bububa/jiagu/classify/bayes"
+)
+
+const (
+	// DEFAULT_WINDOW 默认否定词及程度副词作用范围
+	DEFAULT_WINDOW int = 3
+	// NEGATED_DEGREE_WEIGHT 否定词修饰程度副词时的权重, 如"不是很好"
+	NEGATED_DEGREE_WEIGHT float64 = -0.5
+	// ADVERSATIVE_WEIGHT 转折词之后分句的权重
+	ADVERSATIVE_WEIGHT float64 = 1.5
+)
+
+var (
+	clauseDelimiters = map[string]struct{}{
+		"，": {}, ",": {}, "。": {}, ".": {}, "！": {}, "!": {}, "？": {}, "?": {},
+		"；": {}, ";": {}, "、": {}, "…": {}, "~": {}, "～": {},
+	}
+	sentenceDelimiters = map[string]struct{}{
+		"。": {}, ".": {}, "！": {}, "!": {}, "？": {}, "?": {},
+	}
+	adversatives = map[string]struct{}{
+		"但": {}, "但是": {}, "可是": {}, "然而": {}, "不过": {}, "却": {}, "只是": {},
+	}
+)
+
+// Phrase 参与计算的情感短语
+type Phrase struct {
+	Text  string  `json:"text,omitempty"`
+	Start int     `json:"start"` // 起始token位置
+	End   int     `json:"end"`   // 结束token位置(不包含)
+	Score float64 `json:"score"`
+}
+
+// Result 情感分析结果
+type Result struct {
+	Score        float64  `json:"score"`                 // 综合得分, 范围[-1, 1]
+	LexiconScore float64  `json:"lexicon_score"`         // 情感词典原始得分
+	BayesScore   float64  `json:"bayes_score,omitempty"` // Bayes模型得分(正面概率-负面概率)
+	Phrases      []Phrase `json:"phrases,omitempty"`
+}
+
+// Analyzer 基于情感词典的情感分析, 处理否定词及程度副词的作用范围
+type Analyzer struct {
+	lexicon     *Lexicon
+	window      int
+	bayes       *bayes.Bayes
+	bayesWeight float64
+	positive    string
+	negative    string
+}
+
+// NewAnalyzer 新建Analyzer
+func NewAnalyzer(lexicon *Lexicon) *Analyzer {
+	return &Analyzer{
+		lexicon:  lexicon,
+		window:   DEFAULT_WINDOW,
+		positive: "positive",
+		negative: "negative",
+	}
+}
+
+// SetWindow 设置否定词及程度副词向前作用的token数
+func (a *Analyzer) SetWindow(window int) {
+	a.window = window
+}
+
+// SetBayes 结合Bayes模型得分, weight为Bayes得分所占比重
+func (a *Analyzer) SetBayes(model *bayes.Bayes, weight float64) {
+	a.bayes = model
+	a.bayesWeight = weight
+}
+
+// SetLabels 设置Bayes模型的正面及负面分类名
+func (a *Analyzer) SetLabels(positive string, negative string) {
+	a.positive = positive
+	a.negative = negative
+}
+
+// Lexicon 获取情感词典
+func (a *Analyzer) Lexicon() *Lexicon {
+	return a.lexicon
+}
+
+// Analyze 对分词结果做情感分析
+func (a *Analyzer) Analyze(words []string) Result {
+	var (
+		result    Result
+		scopeFrom int // 当前作用范围起始位置
+		weight    = 1.0
+	)
+	for idx, word := range words {
+		if _, found := clauseDelimiters[word]; found {
+			scopeFrom = idx + 1
+			if _, found := sentenceDelimiters[word]; found {
+				weight = 1
+			}
+			continue
+		}
+		if _, found := adversatives[word]; found {
+			scopeFrom = idx + 1
+			weight = ADVERSATIVE_WEIGHT
+			continue
+		}
+		score, found := a.lexicon.Sentiment(word)
+		if !found || a.isModifier(words, idx) {
+			continue
+		}
+		score, start := a.modify(words, idx, scopeFrom, score)
+		score *= weight
+		result.LexiconScore += score
+		result.Phrases = append(result.Phrases, Phrase{
+			Text:  strings.Join(words[start:idx+1], ""),
+			Start: start,
+			End:   idx + 1,
+			Score: score,
+		})
+		scopeFrom = idx + 1
+	}
+	result.Score = math.Tanh(result.LexiconScore)
+	if a.bayes != nil && a.bayesWeight > 0 {
+		result.BayesScore = a.bayesScore(a.bayes, words)
+		result.Score = (1-a.bayesWeight)*result.Score + a.bayesWeight*result.BayesScore
+	}
+	return result
+}
+
+// modify 根据作用范围内的否定词及程度副词调整情感词得分, 返回调整后得分及短语起始位置
+func (a *Analyzer) modify(words []string, idx int, scopeFrom int, score float64) (float64, int) {
+	start := idx
+	var seenDegree bool
+	for j := idx - 1; j >= scopeFrom && j >= idx-a.window; j-- {
+		if degree, found := a.lexicon.Degree(words[j]); found {
+			score *= degree
+			seenDegree = true
+		} else if a.lexicon.IsNegation(words[j]) {
+			if seenDegree {
+				score *= NEGATED_DEGREE_WEIGHT
+			} else {
+				score *= -1
+			}
+		} else {
+			continue
+		}
+		start = j
+	}
+	return score, start
+}
+
+// bayesScore Bayes模型得分, 正面概率-负面概率
+func (a *Analyzer) bayesScore(model *bayes.Bayes, words []string) float64 {
+	var score float64
+	for _, p := range model.ClassifyProba(words) {
+		switch p.Category {
+		case a.positive:
+			score += p.Value
+		case a.negative:
+			score -= p.Value
+		}
+	}
+	return score
+}
+
+// isModifier 同时为情感词及程度副词且后面紧跟情感词时作为程度副词, 如"好漂亮"
+func (a *Analyzer) isModifier(words []string, idx int) bool {
+	if idx+1 >= len(words) {
+		return false
+	}
+	if _, found := a.lexicon.Degree(words[idx]); !found {
+		return false
+	}
+	_, found := a.lexicon.Sentiment(words[idx+1])
+	return found
+}
@@ -0,0 +1,266 @@
+package sentiment
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bububa/jiagu/classify/bayes"
+)
+
+// DEFAULT_ASPECT_WINDOW 默认评价对象与评价词之间的最大距离
+const DEFAULT_ASPECT_WINDOW int = 4
+
+// Polarity 情感倾向
+type Polarity = string
+
+const (
+	// Positive_Polarity 正面
+	Positive_Polarity Polarity = "positive"
+	// Negative_Polarity 负面
+	Negative_Polarity Polarity = "negative"
+	// Neutral_Polarity 中性
+	Neutral_Polarity Polarity = "neutral"
+)
+
+var (
+	defaultAspectTags  = []string{"n", "nz", "nl", "ni", "j"}
+	defaultOpinionTags = []string{"a", "i"}
+)
+
+// AspectOpinion 评价对象及评价词
+type AspectOpinion struct {
+	Aspect       string   `json:"aspect,omitempty"`
+	Opinion      string   `json:"opinion,omitempty"` // 评价短语, 包含否定词及程度副词
+	AspectStart  int      `json:"aspect_start"`      // 评价对象起始token位置
+	AspectEnd    int      `json:"aspect_end"`        // 评价对象结束token位置(不包含)
+	OpinionStart int      `json:"opinion_start"`     // 评价短语起始token位置
+	OpinionEnd   int      `json:"opinion_end"`       // 评价短语结束token位置(不包含)
+	Score        float64  `json:"score"`
+	Polarity     Polarity `json:"polarity,omitempty"`
+}
+
+// AspectExtractor 基于词性的评价对象-评价词抽取
+type AspectExtractor struct {
+	analyzer    *Analyzer
+	bayes       *bayes.Bayes
+	window      int
+	aspectTags  map[string]struct{}
+	opinionTags map[string]struct{}
+	aspects     map[string]struct{}
+}
+
+// NewAspectExtractor 新建AspectExtractor, 评价词得分优先使用analyzer的情感词典
+func NewAspectExtractor(analyzer *Analyzer) *AspectExtractor {
+	e := &AspectExtractor{
+		analyzer: analyzer,
+		window:   DEFAULT_ASPECT_WINDOW,
+		aspects:  make(map[string]struct{}),
+	}
+	e.SetAspectTags(defaultAspectTags)
+	e.SetOpinionTags(defaultOpinionTags)
+	return e
+}
+
+// SetWindow 设置评价对象与评价词之间的最大token数
+func (e *AspectExtractor) SetWindow(window int) {
+	e.window = window
+}
+
+// SetBayes 设置Bayes模型, 评价词不在情感词典中时使用
+func (e *AspectExtractor) SetBayes(model *bayes.Bayes) {
+	e.bayes = model
+}
+
+// SetAspectTags 设置评价对象词性
+func (e *AspectExtractor) SetAspectTags(tags []string) {
+	e.aspectTags = make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		e.aspectTags[tag] = struct{}{}
+	}
+}
+
+// SetOpinionTags 设置评价词词性, 情感词典中的词总是作为评价词
+func (e *AspectExtractor) SetOpinionTags(tags []string) {
+	e.opinionTags = make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		e.opinionTags[tag] = struct{}{}
+	}
+}
+
+// AddAspects 添加评价对象词, 不受词性限制
+func (e *AspectExtractor) AddAspects(words []string) {
+	for _, word := range words {
+		e.aspects[word] = struct{}{}
+	}
+}
+
+// Extract 根据分词及词性抽取评价对象-评价词
+func (e *AspectExtractor) Extract(words []string, tags []string) []AspectOpinion {
+	var (
+		ret        []AspectOpinion
+		scopes     = make([]int, len(words)) // 各token所在分句的起始位置
+		clause     int
+		lexicon    = e.analyzer.lexicon
+		isAspectAt = func(idx int) bool {
+			if _, found := e.aspects[words[idx]]; found {
+				return true
+			}
+			if _, found := lexicon.Sentiment(words[idx]); found {
+				return false
+			}
+			_, found := e.aspectTags[tagAt(tags, idx)]
+			return found
+		}
+	)
+	for idx, word := range words {
+		_, isDelimiter := clauseDelimiters[word]
+		_, isAdversative := adversatives[word]
+		if isDelimiter || isAdversative {
+			clause = idx + 1
+		}
+		scopes[idx] = clause
+	}
+	for idx, word := range words {
+		if !e.isOpinion(words, tags, idx) || isAspectAt(idx) {
+			continue
+		}
+		aspectStart, aspectEnd := -1, -1
+		for j := idx - 1; j >= 0 && j >= idx-e.window && scopes[j] == scopes[idx]; j-- {
+			if isAspectAt(j) {
+				aspectEnd = j + 1
+				break
+			}
+		}
+		if aspectEnd < 0 {
+			for j := idx + 1; j < len(words) && j <= idx+e.window && scopes[j] == scopes[idx]; j++ {
+				if isAspectAt(j) {
+					aspectStart = j
+					break
+				}
+			}
+			if aspectStart < 0 {
+				continue
+			}
+			for aspectEnd = aspectStart + 1; aspectEnd < len(words) && isAspectAt(aspectEnd); aspectEnd++ {
+			}
+		} else {
+			for aspectStart = aspectEnd - 1; aspectStart > scopes[idx] && isAspectAt(aspectStart-1); aspectStart-- {
+			}
+		}
+		scopeFrom := scopes[idx]
+		if aspectEnd <= idx {
+			scopeFrom = aspectEnd
+		}
+		// 词典及模型中均未出现的评价词为中性, 避免模型只按先验概率判断
+		score, ok := lexicon.Sentiment(word)
+		if !ok && e.bayes != nil && e.bayes.Exists(word) {
+			score = e.analyzer.bayesScore(e.bayes, []string{word})
+		}
+		score, start := e.analyzer.modify(words, idx, scopeFrom, score)
+		ret = append(ret, AspectOpinion{
+			Aspect:       strings.Join(words[aspectStart:aspectEnd], ""),
+			Opinion:      strings.Join(words[start:idx+1], ""),
+			AspectStart:  aspectStart,
+			AspectEnd:    aspectEnd,
+			OpinionStart: start,
+			OpinionEnd:   idx + 1,
+			Score:        score,
+			Polarity:     polarityOf(score),
+		})
+	}
+	return ret
+}
+
+// isOpinion 判断是否为评价词
+func (e *AspectExtractor) isOpinion(words []string, tags []string, idx int) bool {
+	if _, found := e.analyzer.lexicon.Sentiment(words[idx]); found {
+		return !e.analyzer.isModifier(words, idx)
+	}
+	if _, found := e.analyzer.lexicon.Degree(words[idx]); found || e.analyzer.lexicon.IsNegation(words[idx]) {
+		return false
+	}
+	_, found := e.opinionTags[tagAt(tags, idx)]
+	return found
+}
+
+func tagAt(tags []string, idx int) string {
+	if idx < len(tags) {
+		return tags[idx]
+	}
+	return ""
+}
+
+func polarityOf(score float64) Polarity {
+	if score > 0 {
+		return Positive_Polarity
+	} else if score < 0 {
+		return Negative_Polarity
+	}
+	return Neutral_Polarity
+}
+
+// AspectSummary 评价对象汇总
+type AspectSummary struct {
+	Aspect   string   `json:"aspect,omitempty"`
+	Mentions int      `json:"mentions"`
+	Positive int      `json:"positive"`
+	Negative int      `json:"negative"`
+	Neutral  int      `json:"neutral"`
+	Score    float64  `json:"score"` // 平均得分
+	Opinions []string `json:"opinions,omitempty"`
+}
+
+// AspectAggregator 对评论语料按评价对象汇总
+type AspectAggregator struct {
+	summaries map[string]*AspectSummary
+	opinions  map[string]map[string]struct{}
+}
+
+// NewAspectAggregator 新建AspectAggregator
+func NewAspectAggregator() *AspectAggregator {
+	return &AspectAggregator{
+		summaries: make(map[string]*AspectSummary),
+		opinions:  make(map[string]map[string]struct{}),
+	}
+}
+
+// Add 添加一条评论的抽取结果
+func (a *AspectAggregator) Add(pairs []AspectOpinion) {
+	for _, pair := range pairs {
+		summary, found := a.summaries[pair.Aspect]
+		if !found {
+			summary = &AspectSummary{Aspect: pair.Aspect}
+			a.summaries[pair.Aspect] = summary
+			a.opinions[pair.Aspect] = make(map[string]struct{})
+		}
+		summary.Score = (summary.Score*float64(summary.Mentions) + pair.Score) / float64(summary.Mentions+1)
+		summary.Mentions++
+		switch polarityOf(pair.Score) {
+		case Positive_Polarity:
+			summary.Positive++
+		case Negative_Polarity:
+			summary.Negative++
+		default:
+			summary.Neutral++
+		}
+		if _, found := a.opinions[pair.Aspect][pair.Opinion]; !found {
+			a.opinions[pair.Aspect][pair.Opinion] = struct{}{}
+			summary.Opinions = append(summary.Opinions, pair.Opinion)
+		}
+	}
+}
+
+// Summaries 按提及次数降序返回汇总结果
+func (a *AspectAggregator) Summaries() []AspectSummary {
+	ret := make([]AspectSummary, 0, len(a.summaries))
+	for _, summary := range a.summaries {
+		ret = append(ret, *summary)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Mentions == ret[j].Mentions {
+			return ret[i].Aspect < ret[j].Aspect
+		}
+		return ret[i].Mentions > ret[j].Mentions
+	})
+	return ret
+}
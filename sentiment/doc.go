@@ -0,0 +1,2 @@
+// Package sentiment 基于情感词典的情感分析
+package sentiment
@@ -0,0 +1,121 @@
+package sentiment
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Lexicon 情感词典, 包含情感词、否定词及程度副词
+type Lexicon struct {
+	sentiments map[string]float64
+	negations  map[string]struct{}
+	degrees    map[string]float64
+	locker     *sync.RWMutex
+}
+
+// NewLexicon 新建Lexicon
+func NewLexicon() *Lexicon {
+	return &Lexicon{
+		sentiments: make(map[string]float64),
+		negations:  make(map[string]struct{}),
+		degrees:    make(map[string]float64),
+		locker:     new(sync.RWMutex),
+	}
+}
+
+// AddSentiment 添加情感词, weight为正表示正面, 为负表示负面
+func (l *Lexicon) AddSentiment(word string, weight float64) {
+	l.locker.Lock()
+	l.sentiments[word] = weight
+	l.locker.Unlock()
+}
+
+// AddNegation 添加否定词
+func (l *Lexicon) AddNegation(word string) {
+	l.locker.Lock()
+	l.negations[word] = struct{}{}
+	l.locker.Unlock()
+}
+
+// AddDegree 添加程度副词, weight为情感强度倍数
+func (l *Lexicon) AddDegree(word string, weight float64) {
+	l.locker.Lock()
+	l.degrees[word] = weight
+	l.locker.Unlock()
+}
+
+// Sentiment 获取情感词权重
+func (l *Lexicon) Sentiment(word string) (float64, bool) {
+	l.locker.RLock()
+	defer l.locker.RUnlock()
+	weight, found := l.sentiments[word]
+	return weight, found
+}
+
+// IsNegation 判断是否为否定词
+func (l *Lexicon) IsNegation(word string) bool {
+	l.locker.RLock()
+	defer l.locker.RUnlock()
+	_, found := l.negations[word]
+	return found
+}
+
+// Degree 获取程度副词权重
+func (l *Lexicon) Degree(word string) (float64, bool) {
+	l.locker.RLock()
+	defer l.locker.RUnlock()
+	weight, found := l.degrees[word]
+	return weight, found
+}
+
+// LoadSentiments 加载情感词, 每行格式: 词\t权重
+func (l *Lexicon) LoadSentiments(r io.Reader) error {
+	return readLines(r, func(fields []string) {
+		if len(fields) != 2 {
+			return
+		}
+		if weight, err := strconv.ParseFloat(fields[1], 64); err == nil {
+			l.AddSentiment(fields[0], weight)
+		}
+	})
+}
+
+// LoadNegations 加载否定词, 每行一个词
+func (l *Lexicon) LoadNegations(r io.Reader) error {
+	return readLines(r, func(fields []string) {
+		l.AddNegation(fields[0])
+	})
+}
+
+// LoadDegrees 加载程度副词, 每行格式: 词\t倍数
+func (l *Lexicon) LoadDegrees(r io.Reader) error {
+	return readLines(r, func(fields []string) {
+		if len(fields) != 2 {
+			return
+		}
+		if weight, err := strconv.ParseFloat(fields[1], 64); err == nil {
+			l.AddDegree(fields[0], weight)
+		}
+	})
+}
+
+func readLines(r io.Reader, fn func([]string)) error {
+	buf := bufio.NewReader(r)
+	for {
+		line, err := buf.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			fn(strings.Split(line, "\t"))
+		}
+		if err != nil {
+			break
+		}
+	}
+	return nil
+}
@@ -1,7 +1,15 @@
 package jiagu
 
 import (
+	"bytes"
+	"compress/gzip"
+	"math"
+	"strings"
 	"testing"
+
+	"github.com/bububa/jiagu/classify"
+	"github.com/bububa/jiagu/classify/bayes"
+	"github.com/bububa/jiagu/sentiment"
 )
 
 // TestSentiment 测试情感分析
@@ -23,3 +31,219 @@ func TestSentiment(t *testing.T) {
 		}
 	}
 }
+
+// TestSentimentTrain 测试训练情感分析模型
+func TestSentimentTrain(t *testing.T) {
+	data := "positive\t开心 喜欢 不错\npositive\t喜欢 满意\nnegative\t讨厌 失望\nnegative\t讨厌 难过 糟糕\n"
+	samples, err := classify.LoadTSV(strings.NewReader(data))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	classify.Tokenize(samples, strings.Fields)
+	trainer := bayes.NewTrainer(nil, nil)
+	model := trainer.Train(samples)
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if err := model.Save(gw); err != nil {
+		t.Error(err)
+		return
+	}
+	gw.Close()
+	if err := LoadSentimentModel(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		sentimentModel = nil
+	}()
+	tests := [][]string{
+		{"喜欢", "开心"},
+		{"失望", "糟糕"},
+	}
+	exps := []string{
+		"positive",
+		"negative",
+	}
+	for idx, words := range tests {
+		cat, prob := Sentiment(words)
+		if cat != exps[idx] {
+			t.Errorf("expects:%s, result:%s, prob:%f", exps[idx], cat, prob)
+		}
+	}
+	if accuracy := trainer.Eval(model, samples); accuracy != 1 {
+		t.Errorf("expects accuracy:1, result:%f", accuracy)
+	}
+}
+
+// TestBayesVariants 测试贝叶斯模型类型及平滑参数
+func TestBayesVariants(t *testing.T) {
+	data := [][]string{
+		{"positive", "开心", "喜欢", "不错"},
+		{"positive", "喜欢", "满意"},
+		{"negative", "讨厌", "失望"},
+		{"negative", "讨厌", "难过", "糟糕"},
+	}
+	variants := []bayes.Variant{bayes.Multinomial_Variant, bayes.Bernoulli_Variant, bayes.Complement_Variant}
+	for _, variant := range variants {
+		for _, alpha := range []float64{0, 1, 0.5} {
+			model := bayes.New()
+			model.SetVariant(variant)
+			model.SetSmoothing(alpha)
+			for _, d := range data {
+				model.Learn(d[0], d[1:])
+			}
+			var buf bytes.Buffer
+			if err := model.Save(&buf); err != nil {
+				t.Error(err)
+				return
+			}
+			loaded, err := bayes.NewFromReader(&buf)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if loaded.Variant() != variant {
+				t.Errorf("expects variant:%s, result:%s", variant, loaded.Variant())
+			}
+			if cat, prob := loaded.Classify([]string{"喜欢", "未知"}); cat != "positive" {
+				t.Errorf("variant:%s, alpha:%f, expects:positive, result:%s, prob:%f", variant, alpha, cat, prob)
+			}
+			if cat, prob := loaded.Classify([]string{"失望", "未知"}); cat != "negative" {
+				t.Errorf("variant:%s, alpha:%f, expects:negative, result:%s, prob:%f", variant, alpha, cat, prob)
+			}
+		}
+	}
+	model := bayes.New()
+	model.Learn("positive", []string{"喜欢"})
+	model.Learn("negative", []string{"讨厌"})
+	model.Forget("positive", []string{"喜欢"})
+	if probe := model.GetCategory("positive"); probe.Exists("喜欢") || probe.GetSum() != 0 || probe.Docs != 0 {
+		t.Errorf("forget failed: %+v", probe)
+	}
+	// 移除分类中不存在的词不影响其他分类的词表
+	model.Learn("positive", []string{"满意"})
+	model.Forget("positive", []string{"满意", "讨厌"})
+	if !model.Exists("讨厌") {
+		t.Error("forget removed a word learned by another category from vocabulary")
+	}
+	// AddWords与Learn混用时先验概率有效
+	model = bayes.New()
+	model.Learn("positive", []string{"喜欢"})
+	model.AddWords("negative", map[string]float64{"讨厌": 2})
+	if cat, prob := model.Classify([]string{"讨厌"}); cat != "negative" || math.IsNaN(prob) || math.IsInf(prob, 0) {
+		t.Errorf("expects:negative, result:%s, prob:%f", cat, prob)
+	}
+}
+
+// TestSentimentProba 测试情感分析分类概率
+func TestSentimentProba(t *testing.T) {
+	words := Seg("今天真的开心")
+	probs := SentimentProba(words)
+	if len(probs) != 2 {
+		t.Errorf("result: %+v, expect 2 categories\n", probs)
+		return
+	}
+	var sum float64
+	for _, p := range probs {
+		sum += p.Value
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("expects sum:1, result:%f", sum)
+	}
+	cat, prob := Sentiment(words)
+	if probs[0].Category != cat || probs[0].Value != prob {
+		t.Errorf("result: %+v, expect: %s %f\n", probs, cat, prob)
+	}
+}
+
+// TestSentimentScore 测试情感词典情感分析
+func TestSentimentScore(t *testing.T) {
+	tests := [][]string{
+		{"很", "好"},
+		{"不是", "很", "好"},
+		{"很", "不", "好"},
+		{"没有", "问题"},
+		{"价格", "便宜", "，", "但是", "质量", "太", "差"},
+	}
+	exps := []int{1, -1, -1, 1, -1}
+	for idx, words := range tests {
+		result := SentimentScore(words)
+		if (result.Score > 0 && exps[idx] < 0) || (result.Score < 0 && exps[idx] > 0) || result.Score == 0 {
+			t.Errorf("words: %v, result: %+v, expect sign: %d", words, result, exps[idx])
+		}
+	}
+	result := SentimentScore([]string{"不是", "很", "好"})
+	if len(result.Phrases) != 1 || result.Phrases[0].Text != "不是很好" || result.Phrases[0].Start != 0 {
+		t.Errorf("result: %+v", result)
+	}
+}
+
+// TestAspects 测试评价对象-评价词抽取
+func TestAspects(t *testing.T) {
+	extractor := sentiment.NewAspectExtractor(SentimentAnalyzer())
+	tests := []struct {
+		words []string
+		tags  []string
+	}{
+		{
+			words: []string{"价格", "很", "便宜", "，", "但是", "质量", "不", "好"},
+			tags:  []string{"n", "d", "a", "w", "c", "n", "d", "a"},
+		},
+		{
+			words: []string{"屏幕", "清晰", "，", "电池", "续航", "太", "差"},
+			tags:  []string{"n", "a", "w", "n", "n", "d", "a"},
+		},
+	}
+	exps := [][]sentiment.AspectOpinion{
+		{
+			{Aspect: "价格", Opinion: "很便宜", Polarity: sentiment.Positive_Polarity},
+			{Aspect: "质量", Opinion: "不好", Polarity: sentiment.Negative_Polarity},
+		},
+		{
+			{Aspect: "屏幕", Opinion: "清晰", Polarity: sentiment.Positive_Polarity},
+			{Aspect: "电池续航", Opinion: "太差", Polarity: sentiment.Negative_Polarity},
+		},
+	}
+	aggregator := sentiment.NewAspectAggregator()
+	for idx, test := range tests {
+		pairs := extractor.Extract(test.words, test.tags)
+		aggregator.Add(pairs)
+		if len(pairs) != len(exps[idx]) {
+			t.Errorf("result: %+v, expect: %+v", pairs, exps[idx])
+			continue
+		}
+		for i, pair := range pairs {
+			exp := exps[idx][i]
+			if pair.Aspect != exp.Aspect || pair.Opinion != exp.Opinion || pair.Polarity != exp.Polarity {
+				t.Errorf("result: %+v, expect: %+v", pair, exp)
+			}
+		}
+	}
+	aggregator.Add(extractor.Extract([]string{"价格", "太", "贵"}, []string{"n", "d", "a"}))
+	summaries := aggregator.Summaries()
+	if len(summaries) != 4 || summaries[0].Aspect != "价格" || summaries[0].Mentions != 2 || summaries[0].Positive != 1 || summaries[0].Negative != 1 {
+		t.Errorf("result: %+v", summaries)
+	}
+}
+
+// TestAspectsBayes 测试词典未收录的评价词使用Bayes模型判断
+func TestAspectsBayes(t *testing.T) {
+	model := bayes.New()
+	model.Learn("positive", []string{"炫酷", "外观"})
+	model.Learn("positive", []string{"喜欢"})
+	model.Learn("positive", []string{"推荐"})
+	model.Learn("negative", []string{"卡顿"})
+	extractor := sentiment.NewAspectExtractor(SentimentAnalyzer())
+	extractor.SetBayes(model)
+	tests := map[string]sentiment.Polarity{
+		"炫酷": sentiment.Positive_Polarity,
+		"奇特": sentiment.Neutral_Polarity,
+	}
+	for opinion, polarity := range tests {
+		pairs := extractor.Extract([]string{"外观", opinion}, []string{"n", "a"})
+		if len(pairs) != 1 || pairs[0].Polarity != polarity {
+			t.Errorf("result: %+v, expect: %s\n", pairs, polarity)
+		}
+	}
+}
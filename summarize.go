@@ -19,3 +19,21 @@ func Summarize(txt string, n int) []string {
 	model := SummarizeInstance()
 	return model.Summary(txt, n)
 }
+
+// SummarizeSentences 生成摘要, budget为句子数或字符数上限, 返回句子包含得分及位置
+func SummarizeSentences(txt string, budget int, unit textrank.BudgetUnit) []textrank.Sentence {
+	model := SummarizeInstance()
+	return model.SummarySentences(txt, budget, unit)
+}
+
+// MultiSummarize 多文档摘要, 去除不同文档间的冗余句子, 返回句子包含来源文档序号
+func MultiSummarize(docs []string, budget int, unit textrank.BudgetUnit) []textrank.Sentence {
+	model := SummarizeInstance()
+	return model.MultiSummary(docs, budget, unit)
+}
+
+// QuerySummarize 查询摘要, query为查询语句或关键词, 返回与查询相关的句子
+func QuerySummarize(txt string, query []string, budget int, unit textrank.BudgetUnit) []textrank.Sentence {
+	model := SummarizeInstance()
+	return model.QuerySummary(txt, query, budget, unit)
+}
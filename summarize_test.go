@@ -1,7 +1,12 @@
 package jiagu
 
 import (
+	"math"
+	"strings"
 	"testing"
+	"unicode/utf8"
+
+	"github.com/bububa/jiagu/textrank"
 )
 
 // TestSummarize 测试提取摘要
@@ -12,9 +17,9 @@ NASA埃姆斯研究中心的科学家拉玛·内曼尼（Rama Nemani）说，“
 NASA文章介绍，在中国为全球绿化进程做出的贡献中，有42%来源于植树造林工程，对于减少土壤侵蚀、空气污染与气候变化发挥了作用。
 据观察者网过往报道，2017年我国全国共完成造林736.2万公顷、森林抚育830.2万公顷。其中，天然林资源保护工程完成造林26万公顷，退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。三北及长江流域等重点防护林体系工程完成造林99.1万公顷。完成国家储备林建设任务68万公顷。`
 	expects := []string{
-		"”NASA文章介绍，在中国为全球绿化进程做出的贡献中，有42%来源于植树造林工程，对于减少土壤侵蚀、空气污染与气候变化发挥了作用。",
 		"京津风沙源治理工程完成造林18.5万公顷。",
 		"三北及长江流域等重点防护林体系工程完成造林99.1万公顷。",
+		"其中，天然林资源保护工程完成造林26万公顷，退耕还林工程完成造林91.2万公顷。",
 	}
 	list := Summarize(txt, 3)
 	if len(list) != len(expects) {
@@ -28,3 +33,218 @@ NASA文章介绍，在中国为全球绿化进程做出的贡献中，有42%来
 		}
 	}
 }
+
+// TestSummarizeSentences 测试去除冗余及限定长度的摘要
+func TestSummarizeSentences(t *testing.T) {
+	txt := "天然林资源保护工程完成造林26万公顷。天然林资源保护工程完成造林26万公顷。退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。完成国家储备林建设任务68万公顷。"
+	model := textrank.NewSummarize(Segment(), Stopwords())
+	model.SetMMR(0.5)
+	model.SetDocumentOrder(true)
+	list := model.SummarySentences(txt, 3, textrank.Sentence_BudgetUnit)
+	if len(list) != 3 {
+		t.Errorf("result: %+v, expect 3 sentences\n", list)
+		return
+	}
+	seen := make(map[string]struct{}, len(list))
+	for idx, sent := range list {
+		if _, found := seen[sent.Text]; found {
+			t.Errorf("duplicated sentence: %+v\n", list)
+		}
+		seen[sent.Text] = struct{}{}
+		if idx > 0 && sent.Position <= list[idx-1].Position {
+			t.Errorf("expects document order, result: %+v\n", list)
+		}
+	}
+	list = model.SummarySentences(txt, 40, textrank.Char_BudgetUnit)
+	var chars int
+	for _, sent := range list {
+		chars += utf8.RuneCountInString(sent.Text)
+	}
+	if len(list) == 0 || chars > 40 {
+		t.Errorf("result: %+v, expect at most 40 chars\n", list)
+	}
+}
+
+// TestMultiSummarize 测试多文档摘要
+func TestMultiSummarize(t *testing.T) {
+	docs := []string{
+		"天然林资源保护工程完成造林26万公顷。退耕还林工程完成造林91.2万公顷。",
+		"天然林资源保护工程完成造林26万公顷。京津风沙源治理工程完成造林18.5万公顷。完成国家储备林建设任务68万公顷。",
+	}
+	list := MultiSummarize(docs, 3, textrank.Sentence_BudgetUnit)
+	if len(list) != 3 {
+		t.Errorf("result: %+v, expect 3 sentences\n", list)
+		return
+	}
+	seen := make(map[string]struct{}, len(list))
+	documents := make(map[int]struct{}, len(docs))
+	for _, sent := range list {
+		if _, found := seen[sent.Text]; found {
+			t.Errorf("duplicated sentence: %+v\n", list)
+		}
+		seen[sent.Text] = struct{}{}
+		documents[sent.Document] = struct{}{}
+		if runes := []rune(docs[sent.Document]); string(runes[sent.Start:sent.End]) != sent.Text {
+			t.Errorf("invalid offset: %+v\n", sent)
+		}
+	}
+	if len(documents) != len(docs) {
+		t.Errorf("result: %+v, expect sentences from every document\n", list)
+	}
+}
+
+// TestSummarizeSimilarity 测试摘要图的句子相似度
+func TestSummarizeSimilarity(t *testing.T) {
+	txt := "天然林资源保护工程完成造林26万公顷。退耕还林工程完成造林91.2万公顷。今天天气很好。京津风沙源治理工程完成造林18.5万公顷。"
+	model := textrank.NewSummarize(Segment(), Stopwords())
+	for _, similarity := range []textrank.Similarity{textrank.NewOverlapSimilarity(), textrank.NewBM25Similarity(), textrank.NewTFIDFSimilarity()} {
+		model.SetSimilarity(similarity)
+		list := model.Summary(txt, 3)
+		if len(list) != 3 {
+			t.Errorf("similarity: %T, result: %+v, expect 3 sentences\n", similarity, list)
+			continue
+		}
+		for _, sent := range list {
+			if sent == "今天天气很好。" {
+				t.Errorf("similarity: %T, result: %+v, expect unrelated sentence excluded\n", similarity, list)
+			}
+		}
+	}
+}
+
+// TestQuerySummarize 测试查询摘要
+func TestQuerySummarize(t *testing.T) {
+	txt := "天然林资源保护工程完成造林26万公顷。退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。今天天气很好，适合出门散步。"
+	expect := "今天天气很好，适合出门散步。"
+	list := QuerySummarize(txt, []string{"天气"}, 1, textrank.Sentence_BudgetUnit)
+	if len(list) != 1 || list[0].Text != expect {
+		t.Errorf("result: %+v, expect: %s\n", list, expect)
+	}
+}
+
+// TestQuerySummarizePropagation 测试查询相关度沿句子相似度传递: 与相关句相似的句子得分高于入度相同的无关句子
+func TestQuerySummarizePropagation(t *testing.T) {
+	txt := "植树造林能够改善生态环境。生态环境改善需要长期坚持。股票市场今天大幅上涨。股票市场上涨吸引投资者。"
+	list := QuerySummarize(txt, []string{"植树造林"}, 4, textrank.Sentence_BudgetUnit)
+	scores := make(map[string]float64, len(list))
+	for _, sent := range list {
+		scores[sent.Text] = sent.Score
+	}
+	neighbor := scores["生态环境改善需要长期坚持。"]
+	for _, unrelated := range []string{"股票市场今天大幅上涨。", "股票市场上涨吸引投资者。"} {
+		if neighbor <= scores[unrelated] {
+			t.Errorf("result: %+v, expect neighbor of the matching sentence to outrank %s\n", list, unrelated)
+		}
+	}
+}
+
+// TestSummarizeLong 测试长文档摘要
+func TestSummarizeLong(t *testing.T) {
+	txt := strings.Repeat("天然林资源保护工程完成造林26万公顷。退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。今天天气很好，适合出门散步。", 200)
+	model := textrank.NewSummarize(Segment(), Stopwords())
+	model.SetDamping(0.9)
+	model.SetMMR(0.7)
+	list := model.Summary(txt, 3)
+	if len(list) != 3 {
+		t.Errorf("result: %+v, expect 3 sentences\n", list)
+	}
+}
+
+// TestSummarizeDamping 测试阻尼系数: 阻尼系数小时查询相关的句子得分最高, 阻尼系数大时与两句都相似的中间句子得分最高
+func TestSummarizeDamping(t *testing.T) {
+	txt := "植树造林能够改善生态环境。生态环境改善需要保护森林资源。森林资源保护需要长期坚持。"
+	model := textrank.NewSummarize(Segment(), Stopwords())
+	for _, c := range []struct {
+		damping float64
+		expect  string
+	}{
+		{damping: 0.1, expect: "植树造林能够改善生态环境。"},
+		{damping: 0.95, expect: "生态环境改善需要保护森林资源。"},
+	} {
+		model.SetDamping(c.damping)
+		list := model.QuerySummary(txt, []string{"植树造林"}, 1, textrank.Sentence_BudgetUnit)
+		if len(list) != 1 || list[0].Text != c.expect {
+			t.Errorf("damping: %v, result: %+v, expect: %s\n", c.damping, list, c.expect)
+		}
+	}
+}
+
+// recordSimilarity 记录摘要图的句子相似度, 用于稠密矩阵的参照计算
+type recordSimilarity struct {
+	textrank.Similarity
+	num        int
+	similarity textrank.SimilarityFunc
+}
+
+// Fit implement Similarity interface
+func (r *recordSimilarity) Fit(sents [][]string) textrank.SimilarityFunc {
+	r.num = len(sents)
+	r.similarity = r.Similarity.Fit(sents)
+	return r.similarity
+}
+
+// TestSummarizeDense 测试稀疏图的句子得分与稠密矩阵的参照计算一致,
+// 参照计算与改为稀疏图前相同, 计算全部句子对的相似度, 每轮按起点上一轮的得分传递
+func TestSummarizeDense(t *testing.T) {
+	txt := "天然林资源保护工程完成造林26万公顷。退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。今天天气很好，适合出门散步。植树造林能够改善生态环境。生态环境改善需要保护森林资源。"
+	const (
+		damping = 0.85
+		tol     = 1e-12
+		maxIter = 1000
+	)
+	similarity := &recordSimilarity{Similarity: textrank.NewOverlapSimilarity()}
+	model := textrank.NewSummarize(Segment(), Stopwords())
+	model.SetSimilarity(similarity)
+	model.SetDamping(damping)
+	model.SetTol(tol)
+	model.SetMaxIter(maxIter)
+	list := model.SummarySentences(txt, 6, textrank.Sentence_BudgetUnit)
+	num := similarity.num
+	if num != 6 || len(list) != num {
+		t.Fatalf("result: %+v, expect 6 sentences\n", list)
+	}
+	// 稠密矩阵计算全部句子对的相似度, 包括没有共同词的句子对
+	weights := make([][]float64, num)
+	degree := make([]float64, num)
+	for i := range weights {
+		weights[i] = make([]float64, num)
+		for j := range weights[i] {
+			if i != j {
+				weights[i][j] = similarity.similarity(i, j)
+				degree[i] += weights[i][j]
+			}
+		}
+		if degree[i] == 0 {
+			degree[i] = 1
+		}
+	}
+	scores := make([]float64, num)
+	for i := range scores {
+		scores[i] = 0.5
+	}
+	for iter := 0; iter < maxIter; iter++ {
+		next := make([]float64, num)
+		var diff float64
+		for i := range next {
+			var added float64
+			for j := range weights {
+				added += scores[j] * weights[j][i] / degree[j]
+			}
+			next[i] = 1 - damping + damping*added
+			diff = math.Max(diff, math.Abs(next[i]-scores[i]))
+		}
+		scores = next
+		if diff < tol {
+			break
+		}
+	}
+	var maxScore float64
+	for _, score := range scores {
+		maxScore = math.Max(maxScore, score)
+	}
+	for _, sent := range list {
+		if expect := scores[sent.Position] / maxScore; math.Abs(sent.Score-expect) > 1e-6 {
+			t.Errorf("sentence: %s, score: %v, expect: %v\n", sent.Text, sent.Score, expect)
+		}
+	}
+}
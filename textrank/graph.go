@@ -0,0 +1,89 @@
+package textrank
+
+import (
+	"sort"
+)
+
+// edge 带权重的边
+type edge struct {
+	node   int
+	weight float64
+}
+
+// graph 稀疏邻接表表示的有权有向图, 内存及每轮迭代的时间与边数成正比
+type graph struct {
+	edges []map[int]float64 // 起点 -> 终点 -> 权重
+}
+
+func newGraph(num int) *graph {
+	return &graph{
+		edges: make([]map[int]float64, num),
+	}
+}
+
+// Len 节点数
+func (g *graph) Len() int {
+	return len(g.edges)
+}
+
+// addEdge 累加from指向to的边权重
+func (g *graph) addEdge(from int, to int, weight float64) {
+	if weight == 0 {
+		return
+	}
+	if g.edges[from] == nil {
+		g.edges[from] = make(map[int]float64)
+	}
+	g.edges[from][to] += weight
+}
+
+// rank 迭代计算TextRank得分, damping为阻尼系数, teleport为各节点的随机跳转偏好(均值为1), 为空时均匀跳转
+func (g *graph) rank(damping float64, maxIter int, tol float64, teleport []float64) []float64 {
+	num := g.Len()
+	// 按起点顺序整理入边及出度, 保证求和顺序稳定
+	var (
+		inEdges = make([][]edge, num)
+		degree  = make([]float64, num)
+	)
+	for from, targets := range g.edges {
+		nodes := make([]int, 0, len(targets))
+		for to := range targets {
+			nodes = append(nodes, to)
+		}
+		sort.Ints(nodes)
+		for _, to := range nodes {
+			degree[from] += targets[to]
+			inEdges[to] = append(inEdges[to], edge{node: from, weight: targets[to]})
+		}
+		if degree[from] < 1e-15 {
+			degree[from] = 1.0
+		}
+	}
+	// 初始分数设置为0.5
+	scores := make([]float64, num)
+	oldScores := make([]float64, num)
+	for idx := range scores {
+		scores[idx] = 0.5
+	}
+	var counter int
+	for scoreDifferency(scores, oldScores, tol) {
+		copy(oldScores, scores)
+		for i, edges := range inEdges {
+			bias := 1.0
+			if teleport != nil {
+				bias = teleport[i]
+			}
+			var addedScore float64
+			for _, e := range edges {
+				// 起点上一轮的得分按边权重占其出度的比例传递
+				addedScore += oldScores[e.node] * e.weight / degree[e.node]
+			}
+			scores[i] = (1-damping)*bias + damping*addedScore
+		}
+		counter++
+		if counter > maxIter {
+			break
+		}
+	}
+	return scores
+}
@@ -0,0 +1,97 @@
+package textrank
+
+import (
+	"sort"
+)
+
+// Offset 片段在原文中的字符位置
+type Offset struct {
+	Start int `json:"start"`
+	End   int `json:"end"` // 不包含
+}
+
+// Keyphrase 关键短语
+type Keyphrase struct {
+	Phrase  string   `json:"phrase,omitempty"`
+	Words   []string `json:"words,omitempty"`
+	Score   float64  `json:"score"` // 归一化得分, 最高分为1
+	Freq    int      `json:"freq"`
+	Offsets []Offset `json:"offsets,omitempty"` // 每次出现在原文中的字符位置
+}
+
+// ExtractPhrases 提取关键短语: 取TextRank得分最高的三分之一的词(至少n个)为候选,
+// 同一句中紧邻的候选词合并为短语, 短语得分为其中各词得分的平均值, allowedPOS的用法同Extract
+func (k *Keywords) ExtractPhrases(txt string, n int, allowedPOS ...string) []Keyphrase {
+	sentTokens := k.tokenize(txt, allowedPOS)
+	keywords := k.rankTokens(sentTokens)
+	if len(keywords) == 0 || n <= 0 {
+		return nil
+	}
+	top := len(keywords) / 3
+	if top < n {
+		top = n
+	}
+	if top > len(keywords) {
+		top = len(keywords)
+	}
+	scores := make(map[string]float64, top)
+	for _, kw := range keywords[:top] {
+		scores[kw.Word] = kw.Score
+	}
+	var (
+		runes   = []rune(txt)
+		phrases []Keyphrase
+		index   = make(map[string]int)
+		run     []token
+	)
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		offset := Offset{Start: run[0].offset.Start, End: run[len(run)-1].offset.End}
+		text := string(runes[offset.Start:offset.End])
+		idx, found := index[text]
+		if !found {
+			idx = len(phrases)
+			index[text] = idx
+			phrase := Keyphrase{Phrase: text}
+			for _, t := range run {
+				phrase.Words = append(phrase.Words, t.word)
+				phrase.Score += scores[t.word]
+			}
+			phrase.Score /= float64(len(run))
+			phrases = append(phrases, phrase)
+		}
+		phrases[idx].Freq++
+		phrases[idx].Offsets = append(phrases[idx].Offsets, offset)
+		run = run[:0]
+	}
+	for _, tokens := range sentTokens {
+		for _, t := range tokens {
+			_, isCandidate := scores[t.word]
+			isCandidate = isCandidate && t.candidate
+			if !isCandidate || (len(run) > 0 && run[len(run)-1].offset.End != t.offset.Start) || len(run) >= k.phraseWords {
+				flush()
+			}
+			if isCandidate {
+				run = append(run, t)
+			}
+		}
+		flush()
+	}
+	sort.SliceStable(phrases, func(i, j int) bool {
+		if phrases[i].Score == phrases[j].Score {
+			return phrases[i].Freq > phrases[j].Freq
+		}
+		return phrases[i].Score > phrases[j].Score
+	})
+	if len(phrases) > n {
+		phrases = phrases[:n]
+	}
+	if maxScore := phrases[0].Score; maxScore > 0 {
+		for idx := range phrases {
+			phrases[idx].Score /= maxScore
+		}
+	}
+	return phrases
+}
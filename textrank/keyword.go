@@ -0,0 +1,57 @@
+package textrank
+
+import "sort"
+
+// Keyword 关键词及其得分
+type Keyword struct {
+	Word  string  `json:"word,omitempty"`
+	Score float64 `json:"score"` // 归一化得分, 最高分为1
+	Freq  int     `json:"freq"`  // 去除停用词后在文本中出现的次数
+}
+
+// KeywordSlice Keyword array
+type KeywordSlice []Keyword
+
+// Len implement sort.Sorter
+func (s KeywordSlice) Len() int { return len(s) }
+
+// Swap implement sort.Sorter
+func (s KeywordSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// Less implement sort.Sorter
+func (s KeywordSlice) Less(i, j int) bool {
+	if s[i].Score == s[j].Score {
+		return s[i].Word > s[j].Word
+	}
+	return s[i].Score < s[j].Score
+}
+
+// MergeKeywords 合并多篇文档的关键词, 得分取平均值, 频次累加, 返回得分最高的n个关键词
+func MergeKeywords(lists [][]Keyword, n int) []Keyword {
+	var (
+		ret   []Keyword
+		index = make(map[string]int)
+	)
+	for _, list := range lists {
+		for _, kw := range list {
+			idx, found := index[kw.Word]
+			if !found {
+				idx = len(ret)
+				index[kw.Word] = idx
+				ret = append(ret, Keyword{Word: kw.Word})
+			}
+			ret[idx].Score += kw.Score
+			ret[idx].Freq += kw.Freq
+		}
+	}
+	if len(lists) > 0 {
+		for idx := range ret {
+			ret[idx].Score /= float64(len(lists))
+		}
+	}
+	sort.Sort(sort.Reverse(KeywordSlice(ret)))
+	if n >= 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
@@ -4,8 +4,11 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/bububa/jiagu/perceptron"
 	"github.com/bububa/jiagu/segment"
+	"github.com/bububa/jiagu/sentence"
 	"github.com/bububa/jiagu/stopwords"
 )
 
@@ -16,25 +19,39 @@ const (
 	DEFAULT_TOL float64 = 0.0001
 	// DEFALUT_WINDOW 默认window
 	DEFAULT_WINDOW int = 2
+	// DEFAULT_PHRASE_WORDS 默认关键短语最多包含的词数
+	DEFAULT_PHRASE_WORDS int = 3
+	// DEFAULT_DAMPING 默认阻尼系数
+	DEFAULT_DAMPING float64 = 0.85
 )
 
+// DefaultAllowedPOS 默认关键词词性: 名词、专名及动词
+var DefaultAllowedPOS = []string{"n", "nl", "nh", "ns", "ni", "nz", "j", "v"}
+
 // Keywords 提取关键词类
 type Keywords struct {
-	maxIter   int
-	window    int
-	tol       float64
-	stopwords *stopwords.Stopwords
-	seg       *segment.Segment
+	maxIter     int
+	window      int
+	tol         float64
+	damping     float64
+	stopwords   *stopwords.Stopwords
+	seg         *segment.Segment
+	pos         *perceptron.Perceptron
+	splitter    *sentence.Splitter
+	phraseWords int
 }
 
 // New 初始化
 func NewKeywords(seg *segment.Segment, stwords *stopwords.Stopwords) *Keywords {
 	return &Keywords{
-		maxIter:   DEFAULT_MAX_ITER,
-		window:    DEFAULT_WINDOW,
-		tol:       DEFAULT_TOL,
-		seg:       seg,
-		stopwords: stwords,
+		maxIter:     DEFAULT_MAX_ITER,
+		window:      DEFAULT_WINDOW,
+		tol:         DEFAULT_TOL,
+		damping:     DEFAULT_DAMPING,
+		seg:         seg,
+		stopwords:   stwords,
+		splitter:    sentence.NewSplitter(),
+		phraseWords: DEFAULT_PHRASE_WORDS,
 	}
 }
 
@@ -48,11 +65,31 @@ func (k *Keywords) SetWindow(window int) {
 	k.window = window
 }
 
+// SetPhraseWords 设置关键短语最多包含的词数
+func (k *Keywords) SetPhraseWords(phraseWords int) {
+	k.phraseWords = phraseWords
+}
+
+// SetSplitter 设置分句器
+func (k *Keywords) SetSplitter(splitter *sentence.Splitter) {
+	k.splitter = splitter
+}
+
 // SetTol 设置tol
 func (k *Keywords) SetTol(tol float64) {
 	k.tol = tol
 }
 
+// SetDamping 设置阻尼系数
+func (k *Keywords) SetDamping(damping float64) {
+	k.damping = damping
+}
+
+// SetPosModel 设置词性标注模型, 用于按词性过滤关键词, 应在提取关键词前设置, 提取过程中修改不是线程安全的
+func (k *Keywords) SetPosModel(model *perceptron.Perceptron) {
+	k.pos = model
+}
+
 // AddStopwords 添加stopword
 func (k *Keywords) AddStopwords(keywords []string) {
 	if k.stopwords == nil {
@@ -84,31 +121,134 @@ func (k *Keywords) LoadStopwrdsFile(filename string) error {
 	return k.stopwords.LoadFile(filename)
 }
 
-// Extract 提取关键词
-func (k *Keywords) Extract(txt string, n int) []string {
-	txt = strings.ReplaceAll(txt, "\n", "")
-	txt = strings.ReplaceAll(txt, "\r", "")
-	txt = strings.TrimSpace(txt)
-	sentences := cutSentence(txt)
-	sents := psegCutStopwords(k.seg, sentences, k.stopwords)
+// Extract 提取关键词, allowedPOS不为空时只保留指定词性的词;
+// 按词性过滤需先通过SetPosModel设置词性标注模型, 未设置时忽略allowedPOS, 返回未按词性过滤的关键词
+func (k *Keywords) Extract(txt string, n int, allowedPOS ...string) []string {
+	keywords := k.ExtractWithScores(txt, n, allowedPOS...)
+	words := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		words = append(words, kw.Word)
+	}
+	return words
+}
+
+// ExtractWithScores 提取关键词, 包含归一化得分及词频, allowedPOS的用法同Extract
+func (k *Keywords) ExtractWithScores(txt string, n int, allowedPOS ...string) []Keyword {
+	keywords := k.rank(txt, allowedPOS)
+	if n <= 0 {
+		return nil
+	}
+	if len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	return keywords
+}
+
+// rank 计算全部词的归一化TextRank得分, 按得分降序排列
+func (k *Keywords) rank(txt string, allowedPOS []string) []Keyword {
+	return k.rankTokens(k.tokenize(txt, allowedPOS))
+}
+
+// rankTokens 使用分词结果中参与计算的词构建词图
+func (k *Keywords) rankTokens(sentTokens [][]token) []Keyword {
+	sents := make([][]string, 0, len(sentTokens))
+	for _, tokens := range sentTokens {
+		words := make([]string, 0, len(tokens))
+		for _, t := range tokens {
+			if t.candidate {
+				words = append(words, t.word)
+			}
+		}
+		sents = append(sents, words)
+	}
 	wordsIndex, indexWords := k.buildVocab(sents)
 	graph := k.createGraph(sents, wordsIndex, k.window)
-	scores := weightMapRank(graph, k.maxIter, k.tol)
+	scores := graph.rank(k.damping, k.maxIter, k.tol, nil)
 	ss := NewScoreSlice(scores)
 	sort.Sort(sort.Reverse(ss))
-	total := ss.Len()
-	if total > n {
-		total = n
+	if ss.Len() == 0 {
+		return nil
 	}
-	words := make([]string, 0, total)
+	freqs := make(map[string]int, len(wordsIndex))
+	for _, kws := range sents {
+		for _, kw := range kws {
+			freqs[kw]++
+		}
+	}
+	maxScore := ss[0].Value
+	keywords := make([]Keyword, 0, ss.Len())
 	for _, s := range ss {
-		if len(words) >= n {
-			break
+		word := indexWords[s.Idx]
+		score := s.Value
+		if maxScore > 0 {
+			score /= maxScore
 		}
-		idx := s.Idx
-		words = append(words, indexWords[idx])
+		keywords = append(keywords, Keyword{
+			Word:  word,
+			Score: score,
+			Freq:  freqs[word],
+		})
 	}
-	return words
+	return keywords
+}
+
+// token 带原文字符位置的词
+type token struct {
+	word      string
+	offset    Offset
+	candidate bool // 未被停用词及词性过滤, 参与TextRank计算
+}
+
+// tokenize 分句分词并计算每个词在原文中的字符位置, 关键词与关键短语使用同一分词结果;
+// 去除停用词, 按词性过滤时先对完整句子标注词性
+func (k *Keywords) tokenize(txt string, allowedPOS []string) [][]token {
+	var allowed map[string]struct{}
+	if len(allowedPOS) > 0 && k.pos != nil {
+		allowed = make(map[string]struct{}, len(allowedPOS))
+		for _, tag := range allowedPOS {
+			allowed[tag] = struct{}{}
+		}
+	}
+	var ret [][]token
+	for _, sent := range k.splitter.Split(txt) {
+		words := k.seg.Seg(sent.Text, segment.Default_SegMode)
+		var tags []string
+		if allowed != nil {
+			for _, class := range k.pos.Predict(words) {
+				tags = append(tags, class.Label)
+			}
+		}
+		var (
+			tokens     []token
+			cursor     int
+			runeCursor = sent.Start
+		)
+		for idx, w := range words {
+			w = strings.TrimSpace(w)
+			if w == "" {
+				continue
+			}
+			pos := strings.Index(sent.Text[cursor:], w)
+			if pos < 0 {
+				continue
+			}
+			start := runeCursor + utf8.RuneCountInString(sent.Text[cursor:cursor+pos])
+			end := start + utf8.RuneCountInString(w)
+			candidate := k.stopwords == nil || !k.stopwords.Exists(w)
+			if candidate && allowed != nil {
+				_, candidate = allowed[tags[idx]]
+			}
+			tokens = append(tokens, token{
+				word:      w,
+				offset:    Offset{Start: start, End: end},
+				candidate: candidate,
+			})
+			cursor += pos + len(w)
+			runeCursor = end
+		}
+		ret = append(ret, tokens)
+	}
+	return ret
 }
 
 func (k *Keywords) buildVocab(sents [][]string) (map[string]int, map[int]string) {
@@ -127,14 +267,8 @@ func (k *Keywords) buildVocab(sents [][]string) (map[string]int, map[int]string)
 	return wordsIndex, indexWords
 }
 
-func (k *Keywords) createGraph(sents [][]string, wordsIndex map[string]int, window int) [][]float64 {
-	// 初始化graph
-	wordsCount := len(wordsIndex)
-	graph := make([][]float64, wordsCount)
-	for idx := range graph {
-		graph[idx] = make([]float64, wordsCount)
-	}
-
+func (k *Keywords) createGraph(sents [][]string, wordsIndex map[string]int, window int) *graph {
+	g := newGraph(len(wordsIndex))
 	for _, kws := range sents {
 		combinedWords := combineWords(kws, window)
 		for _, ws := range combinedWords {
@@ -146,9 +280,9 @@ func (k *Keywords) createGraph(sents [][]string, wordsIndex map[string]int, wind
 			if !found {
 				continue
 			}
-			graph[idx1][idx2]++
-			graph[idx2][idx1]++
+			g.addEdge(idx1, idx2, 1)
+			g.addEdge(idx2, idx1, 1)
 		}
 	}
-	return graph
+	return g
 }
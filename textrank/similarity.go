@@ -0,0 +1,197 @@
+package textrank
+
+import (
+	"math"
+)
+
+const (
+	// DEFAULT_BM25_K1 默认BM25词频饱和参数k1
+	DEFAULT_BM25_K1 float64 = 1.2
+	// DEFAULT_BM25_B 默认BM25长度归一化参数b
+	DEFAULT_BM25_B float64 = 0.75
+	// DEFAULT_BM25_EPSILON 默认BM25负IDF替换比例, 负IDF替换为平均IDF乘以epsilon
+	DEFAULT_BM25_EPSILON float64 = 0.25
+)
+
+// SimilarityFunc 句子i到句子j的相似度, 即摘要图中i指向j的边权重
+type SimilarityFunc func(i int, j int) float64
+
+// Similarity 摘要图的句子相似度, 没有共同词的句子对不计算相似度, 视为0
+type Similarity interface {
+	// Fit 根据分词后的全部句子计算统计量, 返回句子间的相似度
+	Fit(sents [][]string) SimilarityFunc
+}
+
+// OverlapSimilarity 共同词数除以两句长度之和的对数, 原始TextRank的相似度
+type OverlapSimilarity struct{}
+
+// NewOverlapSimilarity 新建OverlapSimilarity
+func NewOverlapSimilarity() *OverlapSimilarity {
+	return &OverlapSimilarity{}
+}
+
+// Fit implement Similarity interface
+func (o *OverlapSimilarity) Fit(sents [][]string) SimilarityFunc {
+	tfs := termFreqs(sents)
+	return func(i int, j int) float64 {
+		var counter int
+		for _, w := range sents[i] {
+			if _, found := tfs[j][w]; found {
+				counter++
+			}
+		}
+		if counter == 0 {
+			return 0
+		}
+		return float64(counter) / math.Log(float64(len(sents[i])+len(sents[j])))
+	}
+}
+
+// BM25Similarity 以句子i为查询、句子j为文档的BM25得分, 适合长句子
+type BM25Similarity struct {
+	k1      float64
+	b       float64
+	epsilon float64
+}
+
+// NewBM25Similarity 新建BM25Similarity
+func NewBM25Similarity() *BM25Similarity {
+	return &BM25Similarity{
+		k1:      DEFAULT_BM25_K1,
+		b:       DEFAULT_BM25_B,
+		epsilon: DEFAULT_BM25_EPSILON,
+	}
+}
+
+// SetK1 设置词频饱和参数k1
+func (bm *BM25Similarity) SetK1(k1 float64) {
+	bm.k1 = k1
+}
+
+// SetB 设置长度归一化参数b
+func (bm *BM25Similarity) SetB(b float64) {
+	bm.b = b
+}
+
+// SetEpsilon 设置负IDF替换比例
+func (bm *BM25Similarity) SetEpsilon(epsilon float64) {
+	bm.epsilon = epsilon
+}
+
+// Fit implement Similarity interface
+func (bm *BM25Similarity) Fit(sents [][]string) SimilarityFunc {
+	var (
+		tfs    = termFreqs(sents)
+		df     = docFreqs(tfs)
+		num    = float64(len(sents))
+		avgLen float64
+	)
+	for _, words := range sents {
+		avgLen += float64(len(words))
+	}
+	if num > 0 {
+		avgLen /= num
+	}
+	if avgLen == 0 {
+		avgLen = 1
+	}
+	idf := make(map[string]float64, len(df))
+	var (
+		avgIDF   float64
+		negative []string
+	)
+	for w, n := range df {
+		v := math.Log((num - n + 0.5) / (n + 0.5))
+		idf[w] = v
+		avgIDF += v
+		if v < 0 {
+			negative = append(negative, w)
+		}
+	}
+	if len(idf) > 0 {
+		avgIDF /= float64(len(idf))
+	}
+	// 出现在一半以上句子中的词IDF为负, 替换为较小的正数
+	for _, w := range negative {
+		idf[w] = bm.epsilon * avgIDF
+	}
+	return func(i int, j int) float64 {
+		var (
+			score  float64
+			docLen = float64(len(sents[j]))
+		)
+		for w := range tfs[i] {
+			freq := tfs[j][w]
+			if freq == 0 {
+				continue
+			}
+			score += idf[w] * freq * (bm.k1 + 1) / (freq + bm.k1*(1-bm.b+bm.b*docLen/avgLen))
+		}
+		return math.Max(score, 0)
+	}
+}
+
+// TFIDFSimilarity 句子TF-IDF向量的余弦相似度
+type TFIDFSimilarity struct{}
+
+// NewTFIDFSimilarity 新建TFIDFSimilarity
+func NewTFIDFSimilarity() *TFIDFSimilarity {
+	return &TFIDFSimilarity{}
+}
+
+// Fit implement Similarity interface
+func (t *TFIDFSimilarity) Fit(sents [][]string) SimilarityFunc {
+	var (
+		tfs     = termFreqs(sents)
+		df      = docFreqs(tfs)
+		num     = float64(len(sents))
+		vectors = make([]map[string]float64, len(tfs))
+		norms   = make([]float64, len(tfs))
+	)
+	for idx, tf := range tfs {
+		vector := make(map[string]float64, len(tf))
+		for w, freq := range tf {
+			v := freq * (math.Log((1+num)/(1+df[w])) + 1)
+			vector[w] = v
+			norms[idx] += v * v
+		}
+		vectors[idx] = vector
+		norms[idx] = math.Sqrt(norms[idx])
+	}
+	return func(i int, j int) float64 {
+		if norms[i] == 0 || norms[j] == 0 {
+			return 0
+		}
+		v1, v2 := vectors[i], vectors[j]
+		if len(v1) > len(v2) {
+			v1, v2 = v2, v1
+		}
+		var dot float64
+		for w, v := range v1 {
+			dot += v * v2[w]
+		}
+		return dot / (norms[i] * norms[j])
+	}
+}
+
+func termFreqs(sents [][]string) []map[string]float64 {
+	ret := make([]map[string]float64, 0, len(sents))
+	for _, words := range sents {
+		tf := make(map[string]float64, len(words))
+		for _, w := range words {
+			tf[w]++
+		}
+		ret = append(ret, tf)
+	}
+	return ret
+}
+
+func docFreqs(tfs []map[string]float64) map[string]float64 {
+	ret := make(map[string]float64)
+	for _, tf := range tfs {
+		for w := range tf {
+			ret[w]++
+		}
+	}
+	return ret
+}
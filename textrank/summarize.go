@@ -2,27 +2,71 @@ package textrank
 
 import (
 	"io"
+	"math"
 	"sort"
-	"strings"
+	"unicode/utf8"
 
 	"github.com/bububa/jiagu/segment"
+	"github.com/bububa/jiagu/sentence"
 	"github.com/bububa/jiagu/stopwords"
 )
 
+// BudgetUnit 摘要长度单位
+type BudgetUnit = string
+
+const (
+	// Sentence_BudgetUnit 按句子数
+	Sentence_BudgetUnit BudgetUnit = "sentence"
+	// Char_BudgetUnit 按字符数
+	Char_BudgetUnit BudgetUnit = "char"
+)
+
+const (
+	// DEFAULT_REDUNDANCY 默认多文档摘要冗余阈值
+	DEFAULT_REDUNDANCY float64 = 0.8
+	// NO_REDUNDANCY 不按冗余阈值跳过句子, 句子间余弦相似度不超过1
+	NO_REDUNDANCY float64 = 2
+	// DEFAULT_QUERY_BIAS 默认查询摘要随机跳转中偏向相关句子的比例
+	DEFAULT_QUERY_BIAS float64 = 0.9
+)
+
+// Sentence 摘要句子
+type Sentence struct {
+	Text     string  `json:"text,omitempty"`
+	Document int     `json:"document"` // 来源文档序号, 单文档摘要为0
+	Position int     `json:"position"` // 在原文中的句子序号
+	Start    int     `json:"start"`    // 在原文中的字符位置
+	End      int     `json:"end"`      // 不包含
+	Score    float64 `json:"score"`    // 归一化TextRank得分, 最高分为1
+}
+
 type Summarize struct {
-	maxIter   int
-	tol       float64
-	stopwords *stopwords.Stopwords
-	seg       *segment.Segment
+	maxIter       int
+	tol           float64
+	damping       float64
+	lambda        float64
+	redundancy    float64
+	queryBias     float64
+	documentOrder bool
+	splitter      *sentence.Splitter
+	similarity    Similarity
+	stopwords     *stopwords.Stopwords
+	seg           *segment.Segment
 }
 
 // New 初始化
 func NewSummarize(seg *segment.Segment, stwords *stopwords.Stopwords) *Summarize {
 	return &Summarize{
-		maxIter:   DEFAULT_MAX_ITER,
-		tol:       DEFAULT_TOL,
-		seg:       seg,
-		stopwords: stwords,
+		maxIter:    DEFAULT_MAX_ITER,
+		tol:        DEFAULT_TOL,
+		damping:    DEFAULT_DAMPING,
+		lambda:     1,
+		redundancy: DEFAULT_REDUNDANCY,
+		queryBias:  DEFAULT_QUERY_BIAS,
+		splitter:   sentence.NewSplitter(),
+		similarity: NewOverlapSimilarity(),
+		seg:        seg,
+		stopwords:  stwords,
 	}
 }
 
@@ -36,6 +80,41 @@ func (s *Summarize) SetTol(tol float64) {
 	s.tol = tol
 }
 
+// SetDamping 设置阻尼系数
+func (s *Summarize) SetDamping(damping float64) {
+	s.damping = damping
+}
+
+// SetMMR 设置MMR权重lambda, 1为只按得分选择, 越小越倾向去除与已选句子相似的句子, 常用0.7
+func (s *Summarize) SetMMR(lambda float64) {
+	s.lambda = lambda
+}
+
+// SetRedundancy 设置多文档摘要冗余阈值, 与已选句子余弦相似度不小于阈值的句子不再选择, 大于1时不去除
+func (s *Summarize) SetRedundancy(redundancy float64) {
+	s.redundancy = redundancy
+}
+
+// SetQueryBias 设置查询摘要随机跳转中偏向相关句子的比例, 0为不考虑查询, 1为只跳转到相关句子
+func (s *Summarize) SetQueryBias(queryBias float64) {
+	s.queryBias = queryBias
+}
+
+// SetDocumentOrder 设置摘要句子按原文顺序输出, 默认按选择顺序
+func (s *Summarize) SetDocumentOrder(documentOrder bool) {
+	s.documentOrder = documentOrder
+}
+
+// SetSplitter 设置分句器
+func (s *Summarize) SetSplitter(splitter *sentence.Splitter) {
+	s.splitter = splitter
+}
+
+// SetSimilarity 设置摘要图的句子相似度, 默认为OverlapSimilarity
+func (s *Summarize) SetSimilarity(similarity Similarity) {
+	s.similarity = similarity
+}
+
 // AddStopwords 添加stopword
 func (s *Summarize) AddStopwords(keywords []string) {
 	if s.stopwords == nil {
@@ -70,46 +149,187 @@ func (s *Summarize) LoadStopwordsFile(filename string) error {
 
 // Summary 生成摘要
 func (s *Summarize) Summary(txt string, n int) []string {
-	txt = strings.ReplaceAll(txt, "\n", "")
-	txt = strings.ReplaceAll(txt, "\r", "")
-	txt = strings.TrimSpace(txt)
-	sentences := cutSentence(txt)
-	sents := psegCutStopwords(s.seg, sentences, s.stopwords)
+	sentences := s.SummarySentences(txt, n, Sentence_BudgetUnit)
+	res := make([]string, 0, len(sentences))
+	for _, sent := range sentences {
+		res = append(res, sent.Text)
+	}
+	return res
+}
+
+// SummarySentences 生成摘要, budget为句子数或字符数上限, 返回句子包含得分及位置
+func (s *Summarize) SummarySentences(txt string, budget int, unit BudgetUnit) []Sentence {
+	candidates := s.split(0, txt)
+	// 单文档摘要只按MMR去除冗余
+	return s.summary(candidates, budget, unit, NO_REDUNDANCY, nil)
+}
+
+// QuerySummary 查询摘要, query为查询语句或关键词, 分词后按与句子的相关度设置PageRank随机跳转概率(topic-sensitive PageRank),
+// 与查询相关的句子及与其相似的句子得分更高
+func (s *Summarize) QuerySummary(txt string, query []string, budget int, unit BudgetUnit) []Sentence {
+	candidates := s.split(0, txt)
+	return s.summary(candidates, budget, unit, NO_REDUNDANCY, query)
+}
+
+// MultiSummary 多文档摘要, 全部文档的句子构成同一个图, 并去除不同文档间的冗余句子,
+// 返回句子包含来源文档序号, 按原文顺序输出时先按文档排序
+func (s *Summarize) MultiSummary(docs []string, budget int, unit BudgetUnit) []Sentence {
+	var candidates []Sentence
+	for idx, txt := range docs {
+		candidates = append(candidates, s.split(idx, txt)...)
+	}
+	return s.summary(candidates, budget, unit, s.redundancy, nil)
+}
+
+// split 分句, 句子得分待计算
+func (s *Summarize) split(document int, txt string) []Sentence {
+	sentences := s.splitter.Split(txt)
+	ret := make([]Sentence, 0, len(sentences))
+	for idx, sent := range sentences {
+		ret = append(ret, Sentence{
+			Text:     sent.Text,
+			Document: document,
+			Position: idx,
+			Start:    sent.Start,
+			End:      sent.End,
+		})
+	}
+	return ret
+}
+
+func (s *Summarize) summary(candidates []Sentence, budget int, unit BudgetUnit, redundancy float64, query []string) []Sentence {
+	texts := make([]string, 0, len(candidates))
+	for _, sent := range candidates {
+		texts = append(texts, sent.Text)
+	}
+	sents := psegCutStopwords(s.seg, texts, s.stopwords)
 	graph := s.createGraph(sents)
-	scores := weightMapRank(graph, s.maxIter, s.tol)
+	scores := graph.rank(s.damping, s.maxIter, s.tol, s.teleport(sents, query))
+	return s.selectSentences(candidates, sents, scores, budget, unit, redundancy)
+}
+
+// teleport 按句子与查询的相关度计算随机跳转偏好, 均值为1, 无查询或均不相关时返回nil
+func (s *Summarize) teleport(sents [][]string, query []string) []float64 {
+	if len(query) == 0 || len(sents) == 0 || s.queryBias <= 0 {
+		return nil
+	}
+	var queryWords []string
+	for _, words := range psegCutStopwords(s.seg, query, s.stopwords) {
+		queryWords = append(queryWords, words...)
+	}
+	var (
+		relevance = make([]float64, len(sents))
+		total     float64
+	)
+	for idx, words := range sents {
+		relevance[idx] = cosineSimilarity(queryWords, words)
+		total += relevance[idx]
+	}
+	if total == 0 {
+		return nil
+	}
+	num := float64(len(sents))
+	for idx, v := range relevance {
+		relevance[idx] = s.queryBias*v*num/total + (1 - s.queryBias)
+	}
+	return relevance
+}
+
+// selectSentences 按MMR在预算内选择句子, 与已选句子相似度不小于redundancy的句子直接跳过
+func (s *Summarize) selectSentences(candidates []Sentence, sents [][]string, scores []float64, budget int, unit BudgetUnit, redundancy float64) []Sentence {
 	ss := NewScoreSlice(scores)
 	sort.Sort(sort.Reverse(ss))
-	total := ss.Len()
-	if total > n {
-		total = n
+	if ss.Len() == 0 || budget <= 0 {
+		return nil
 	}
-	res := make([]string, 0, total)
-	for _, s := range ss {
-		if len(res) >= n {
+	maxScore := ss[0].Value
+	if maxScore <= 0 {
+		maxScore = 1
+	}
+	var (
+		res  []Sentence
+		used int
+		// 每个句子与已选句子的最大相似度, 每轮只与新选中的句子比较
+		similarities = make([]float64, len(sents))
+		last         = -1
+	)
+	for len(ss) > 0 {
+		var (
+			best      = -1
+			bestValue = math.Inf(-1)
+			kept      = ss[:0]
+		)
+		for _, candidate := range ss {
+			if last >= 0 && (s.lambda < 1 || redundancy <= 1) {
+				similarities[candidate.Idx] = math.Max(similarities[candidate.Idx], cosineSimilarity(sents[candidate.Idx], sents[last]))
+			}
+			maxSimilarity := similarities[candidate.Idx]
+			if last >= 0 && maxSimilarity >= redundancy {
+				continue
+			}
+			kept = append(kept, candidate)
+			value := s.lambda*candidate.Value/maxScore - (1-s.lambda)*maxSimilarity
+			if value > bestValue {
+				best, bestValue = len(kept)-1, value
+			}
+		}
+		ss = kept
+		if best < 0 {
 			break
 		}
-		idx := s.Idx
-		res = append(res, sentences[idx])
+		candidate := ss[best]
+		ss = append(ss[:best], ss[best+1:]...)
+		sent := candidates[candidate.Idx]
+		length := 1
+		if unit == Char_BudgetUnit {
+			length = utf8.RuneCountInString(sent.Text)
+		}
+		if used+length > budget {
+			if unit == Char_BudgetUnit {
+				continue
+			}
+			break
+		}
+		used += length
+		sent.Score = candidate.Value / maxScore
+		res = append(res, sent)
+		last = candidate.Idx
+	}
+	if s.documentOrder {
+		sort.SliceStable(res, func(i, j int) bool {
+			if res[i].Document == res[j].Document {
+				return res[i].Position < res[j].Position
+			}
+			return res[i].Document < res[j].Document
+		})
 	}
 	return res
 }
 
-func (s *Summarize) createGraph(sents [][]string) [][]float64 {
-	num := len(sents)
-	graph := make([][]float64, num)
-	for idx := range graph {
-		graph[idx] = make([]float64, num)
+// createGraph 构建句子图, 只计算有共同词的句子对, 避免两两比较全部句子
+func (s *Summarize) createGraph(sents [][]string) *graph {
+	g := newGraph(len(sents))
+	postings := make(map[string][]int)
+	for idx, words := range sents {
+		for _, w := range words {
+			if list := postings[w]; len(list) == 0 || list[len(list)-1] != idx {
+				postings[w] = append(list, idx)
+			}
+		}
 	}
-	var i int
-	for i < num {
-		var j int
-		for j < num {
+	similarity := s.similarity.Fit(sents)
+	for i, words := range sents {
+		neighbors := make(map[int]struct{})
+		for _, w := range words {
+			for _, j := range postings[w] {
+				neighbors[j] = struct{}{}
+			}
+		}
+		for j := range neighbors {
 			if i != j {
-				graph[i][j] = sentencesSimilarity(sents[i], sents[j])
+				g.addEdge(i, j, similarity(i, j))
 			}
-			j++
 		}
-		i++
 	}
-	return graph
+	return g
 }
@@ -0,0 +1,65 @@
+package jiagu
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/bububa/jiagu/textrank"
+	"github.com/bububa/jiagu/tfidf"
+)
+
+var (
+	tfidfModel   *tfidf.Keywords
+	tfidfPosOnce sync.Once
+)
+
+// TFIDFInstance get tfidfModel singleton, 默认IDF表由分词字典词频估算
+func TFIDFInstance() *tfidf.Keywords {
+	if tfidfModel == nil {
+		fd, err := dictFS.Open(fmt.Sprintf("dict/%s", VOCAB_DICT))
+		if err != nil {
+			panic(err)
+		}
+		defer fd.Close()
+		idf := tfidf.NewIDF()
+		if err := idf.LoadFreqDict(fd); err != nil {
+			panic(err)
+		}
+		tfidfModel = tfidf.NewKeywords(Segment(), Stopwords(), idf)
+	}
+	return tfidfModel
+}
+
+// LoadIDF 加载自定义IDF表, 每行格式: 词\tIDF
+func LoadIDF(r io.Reader) error {
+	idf, err := tfidf.NewIDFFromReader(r)
+	if err != nil {
+		return err
+	}
+	TFIDFInstance().SetIDF(idf)
+	return nil
+}
+
+// TFIDFKeywords 基于TF-IDF的关键词提取, allowedPOS不为空时只保留指定词性的词
+func TFIDFKeywords(txt string, n int, allowedPOS ...string) []string {
+	model := tfidfWithPOS(allowedPOS)
+	return model.Extract(txt, n, allowedPOS...)
+}
+
+// TFIDFKeywordsWithScores 基于TF-IDF的关键词提取, 包含归一化得分及词频
+func TFIDFKeywordsWithScores(txt string, n int, allowedPOS ...string) []textrank.Keyword {
+	model := tfidfWithPOS(allowedPOS)
+	return model.ExtractWithScores(txt, n, allowedPOS...)
+}
+
+// tfidfWithPOS 按词性过滤时才加载词性标注模型, 只设置一次
+func tfidfWithPOS(allowedPOS []string) *tfidf.Keywords {
+	model := TFIDFInstance()
+	if len(allowedPOS) > 0 {
+		tfidfPosOnce.Do(func() {
+			model.SetPosModel(PosModel())
+		})
+	}
+	return model
+}
@@ -0,0 +1,46 @@
+package tfidf
+
+import "math"
+
+// Builder 从语料统计文档频率生成IDF表
+type Builder struct {
+	docs float64
+	df   map[string]float64
+}
+
+// NewBuilder 新建Builder
+func NewBuilder() *Builder {
+	return &Builder{
+		df: make(map[string]float64),
+	}
+}
+
+// Add 添加一篇已分词的文档, 同一文档中重复的词只计一次
+func (b *Builder) Add(words []string) {
+	seen := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if _, found := seen[word]; found {
+			continue
+		}
+		seen[word] = struct{}{}
+		b.df[word]++
+	}
+	b.docs++
+}
+
+// Docs 文档数
+func (b *Builder) Docs() int {
+	return int(b.docs)
+}
+
+// Build 生成IDF表, 忽略文档频率小于minDF的词, IDF=log((1+文档数)/(1+文档频率))+1
+func (b *Builder) Build(minDF int) *IDF {
+	idf := NewIDF()
+	for word, df := range b.df {
+		if df < float64(minDF) {
+			continue
+		}
+		idf.Set(word, math.Log((1+b.docs)/(1+df))+1)
+	}
+	return idf
+}
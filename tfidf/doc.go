@@ -0,0 +1,2 @@
+// Package tfidf 基于TF-IDF的关键词提取
+package tfidf
@@ -0,0 +1,182 @@
+package tfidf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// IDF 逆文档频率表, 未收录的词使用中位数
+type IDF struct {
+	values map[string]float64
+	median float64
+	dirty  bool
+	locker *sync.RWMutex
+}
+
+// NewIDF 新建IDF
+func NewIDF() *IDF {
+	return &IDF{
+		values: make(map[string]float64),
+		locker: new(sync.RWMutex),
+	}
+}
+
+// NewIDFFromReader 从io.Reader加载IDF表
+func NewIDFFromReader(r io.Reader) (*IDF, error) {
+	idf := NewIDF()
+	if err := idf.Load(r); err != nil {
+		return nil, err
+	}
+	return idf, nil
+}
+
+// Set 设置词的IDF
+func (i *IDF) Set(word string, value float64) {
+	i.locker.Lock()
+	i.values[word] = value
+	i.dirty = true
+	i.locker.Unlock()
+}
+
+// Get 获取词的IDF
+func (i *IDF) Get(word string) (float64, bool) {
+	i.locker.RLock()
+	defer i.locker.RUnlock()
+	value, found := i.values[word]
+	return value, found
+}
+
+// Value 获取词的IDF, 未收录的词返回中位数
+func (i *IDF) Value(word string) float64 {
+	if value, found := i.Get(word); found {
+		return value
+	}
+	return i.Median()
+}
+
+// Median IDF中位数
+func (i *IDF) Median() float64 {
+	i.locker.RLock()
+	if !i.dirty {
+		defer i.locker.RUnlock()
+		return i.median
+	}
+	i.locker.RUnlock()
+	i.locker.Lock()
+	defer i.locker.Unlock()
+	values := make([]float64, 0, len(i.values))
+	for _, value := range i.values {
+		values = append(values, value)
+	}
+	sort.Float64s(values)
+	i.median = 0
+	if l := len(values); l > 0 {
+		i.median = values[l/2]
+	}
+	i.dirty = false
+	return i.median
+}
+
+// Len 收录的词数
+func (i *IDF) Len() int {
+	i.locker.RLock()
+	defer i.locker.RUnlock()
+	return len(i.values)
+}
+
+// Load 加载IDF表, 每行格式: 词\tIDF
+func (i *IDF) Load(r io.Reader) error {
+	return readFields(r, func(word string, value float64) {
+		i.Set(word, value)
+	})
+}
+
+// LoadFile 加载IDF表文件
+func (i *IDF) LoadFile(loc string) error {
+	fd, err := os.Open(loc)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	return i.Load(fd)
+}
+
+// LoadFreqDict 根据词频字典估算IDF, 每行格式: 词\t词频, IDF=log(总词频/词频)
+func (i *IDF) LoadFreqDict(r io.Reader) error {
+	freqs := make(map[string]float64)
+	var total float64
+	if err := readFields(r, func(word string, freq float64) {
+		if freq <= 0 {
+			return
+		}
+		freqs[word] += freq
+		total += freq
+	}); err != nil {
+		return err
+	}
+	for word, freq := range freqs {
+		i.Set(word, math.Log(total/freq))
+	}
+	return nil
+}
+
+// Save 保存IDF表, 按词排序
+func (i *IDF) Save(w io.Writer) error {
+	i.locker.RLock()
+	words := make([]string, 0, len(i.values))
+	for word := range i.values {
+		words = append(words, word)
+	}
+	i.locker.RUnlock()
+	sort.Strings(words)
+	buf := bufio.NewWriter(w)
+	for _, word := range words {
+		value, _ := i.Get(word)
+		if _, err := fmt.Fprintf(buf, "%s\t%.6f\n", word, value); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// SaveFile 保存IDF表到文件
+func (i *IDF) SaveFile(loc string) error {
+	fd, err := os.OpenFile(loc, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	return i.Save(fd)
+}
+
+// readFields 读取 词\t数值 格式的文件, 也支持空格分隔
+func readFields(r io.Reader, fn func(string, float64)) error {
+	buf := bufio.NewReader(r)
+	for {
+		line, err := buf.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 2 {
+			fields = strings.Fields(line)
+		}
+		if len(fields) >= 2 {
+			if value, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64); err == nil {
+				fn(fields[0], value)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package tfidf
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bububa/jiagu/perceptron"
+	"github.com/bububa/jiagu/segment"
+	"github.com/bububa/jiagu/stopwords"
+	"github.com/bububa/jiagu/textrank"
+)
+
+// Keywords 基于TF-IDF提取关键词类
+type Keywords struct {
+	idf       *IDF
+	stopwords *stopwords.Stopwords
+	seg       *segment.Segment
+	pos       *perceptron.Perceptron
+}
+
+// NewKeywords 初始化
+func NewKeywords(seg *segment.Segment, stwords *stopwords.Stopwords, idf *IDF) *Keywords {
+	if idf == nil {
+		idf = NewIDF()
+	}
+	return &Keywords{
+		idf:       idf,
+		seg:       seg,
+		stopwords: stwords,
+	}
+}
+
+// SetIDF 设置IDF表
+func (k *Keywords) SetIDF(idf *IDF) {
+	k.idf = idf
+}
+
+// IDF 获取IDF表
+func (k *Keywords) IDF() *IDF {
+	return k.idf
+}
+
+// SetPosModel 设置词性标注模型, 用于按词性过滤关键词, 应在提取关键词前设置, 提取过程中修改不是线程安全的
+func (k *Keywords) SetPosModel(model *perceptron.Perceptron) {
+	k.pos = model
+}
+
+// AddStopwords 添加stopword
+func (k *Keywords) AddStopwords(keywords []string) {
+	if k.stopwords == nil {
+		return
+	}
+	k.stopwords.Add(keywords)
+}
+
+// DelStopwords 删除stopword
+func (k *Keywords) DelStopwords(keywords []string) {
+	if k.stopwords == nil {
+		return
+	}
+	k.stopwords.Del(keywords)
+}
+
+// LoadStopwords 加载stopwords
+func (k *Keywords) LoadStopwords(r io.Reader) error {
+	if k.stopwords == nil {
+		k.stopwords = stopwords.New()
+	}
+	return k.stopwords.Load(r)
+}
+
+// LoadStopwordsFile 加载stopwords文件
+func (k *Keywords) LoadStopwordsFile(filename string) error {
+	if k.stopwords == nil {
+		k.stopwords = stopwords.New()
+	}
+	return k.stopwords.LoadFile(filename)
+}
+
+// Extract 提取关键词, allowedPOS不为空时只保留指定词性的词;
+// 按词性过滤需先通过SetPosModel设置词性标注模型, 未设置时忽略allowedPOS, 返回未按词性过滤的关键词
+func (k *Keywords) Extract(txt string, n int, allowedPOS ...string) []string {
+	keywords := k.ExtractWithScores(txt, n, allowedPOS...)
+	words := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		words = append(words, kw.Word)
+	}
+	return words
+}
+
+// ExtractWithScores 提取关键词, 包含归一化得分及词频, allowedPOS的用法同Extract
+func (k *Keywords) ExtractWithScores(txt string, n int, allowedPOS ...string) []textrank.Keyword {
+	words := k.cut(strings.TrimSpace(txt), allowedPOS)
+	if len(words) == 0 || n <= 0 {
+		return nil
+	}
+	freqs := make(map[string]int)
+	for _, w := range words {
+		freqs[w]++
+	}
+	keywords := make([]textrank.Keyword, 0, len(freqs))
+	for w, freq := range freqs {
+		keywords = append(keywords, textrank.Keyword{
+			Word:  w,
+			Score: float64(freq) / float64(len(words)) * k.idf.Value(w),
+			Freq:  freq,
+		})
+	}
+	sort.Sort(sort.Reverse(textrank.KeywordSlice(keywords)))
+	if len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	if maxScore := keywords[0].Score; maxScore > 0 {
+		for idx := range keywords {
+			keywords[idx].Score /= maxScore
+		}
+	}
+	return keywords
+}
+
+// cut 分词并去除停用词及单字词, 按词性过滤时先对完整文本标注词性
+func (k *Keywords) cut(txt string, allowedPOS []string) []string {
+	words := k.seg.Seg(txt, segment.Default_SegMode)
+	var allowed map[string]struct{}
+	if len(allowedPOS) > 0 && k.pos != nil {
+		allowed = make(map[string]struct{}, len(allowedPOS))
+		for _, tag := range allowedPOS {
+			allowed[tag] = struct{}{}
+		}
+		tags := k.pos.Predict(words)
+		filtered := words[:0]
+		for idx, w := range words {
+			if _, found := allowed[tags[idx].Label]; found {
+				filtered = append(filtered, w)
+			}
+		}
+		words = filtered
+	}
+	ret := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if utf8.RuneCountInString(w) < 2 || (k.stopwords != nil && k.stopwords.Exists(w)) {
+			continue
+		}
+		ret = append(ret, w)
+	}
+	return ret
+}
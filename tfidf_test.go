@@ -0,0 +1,74 @@
+package jiagu
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/bububa/jiagu/tfidf"
+)
+
+// TestTFIDFKeywords 测试TF-IDF关键词提取
+func TestTFIDFKeywords(t *testing.T) {
+	txt := "据观察者网过往报道，2017年我国全国共完成造林736.2万公顷、森林抚育830.2万公顷。其中，天然林资源保护工程完成造林26万公顷，退耕还林工程完成造林91.2万公顷。京津风沙源治理工程完成造林18.5万公顷。"
+	kws := TFIDFKeywordsWithScores(txt, 3)
+	words := TFIDFKeywords(txt, 3)
+	if len(kws) != 3 || len(words) != 3 {
+		t.Errorf("result: %+v, expect 3 keywords\n", kws)
+		return
+	}
+	if kws[0].Score != 1 {
+		t.Errorf("expects top score:1, result: %+v\n", kws)
+	}
+	for idx, kw := range kws {
+		if kw.Word != words[idx] || kw.Freq == 0 {
+			t.Errorf("result: %+v, expect: %+v\n", kws, words)
+			break
+		}
+	}
+}
+
+// TestIDF 测试从语料生成IDF表
+func TestIDF(t *testing.T) {
+	builder := tfidf.NewBuilder()
+	builder.Add([]string{"造林", "公顷", "造林"})
+	builder.Add([]string{"造林", "工程"})
+	builder.Add([]string{"造林", "绿化"})
+	idf := builder.Build(1)
+	if idf.Len() != 4 {
+		t.Errorf("expects 4 words, result:%d", idf.Len())
+	}
+	common, _ := idf.Get("造林")
+	rare, _ := idf.Get("公顷")
+	if math.Abs(common-1) > 1e-9 || math.Abs(rare-(math.Log(2)+1)) > 1e-9 {
+		t.Errorf("造林:%f, 公顷:%f", common, rare)
+	}
+	if median := idf.Value("未知"); median != rare {
+		t.Errorf("expects median:%f, result:%f", rare, median)
+	}
+	if idf := builder.Build(2); idf.Len() != 1 {
+		t.Errorf("expects 1 word with mindf 2, result:%d", idf.Len())
+	}
+	var buf bytes.Buffer
+	if err := idf.Save(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	loaded, err := tfidf.NewIDFFromReader(&buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if value, found := loaded.Get("公顷"); !found || math.Abs(value-rare) > 1e-6 {
+		t.Errorf("expects 公顷:%f, result:%f", rare, value)
+	}
+	freqIDF := tfidf.NewIDF()
+	if err := freqIDF.LoadFreqDict(strings.NewReader("造林\t30\n公顷\t10\n")); err != nil {
+		t.Error(err)
+		return
+	}
+	if value, _ := freqIDF.Get("公顷"); math.Abs(value-math.Log(4)) > 1e-9 {
+		t.Errorf("expects 公顷:%f, result:%f", math.Log(4), value)
+	}
+}
@@ -108,6 +108,53 @@ func (s *StringCounter) Sum() int {
 	return total
 }
 
+// Merge 合并另一个StringCounter的计数
+func (s *StringCounter) Merge(other *StringCounter) {
+	if s == other {
+		s.locker.Lock()
+		defer s.locker.Unlock()
+		for key := range s.mp {
+			s.mp[key] *= 2
+		}
+		return
+	}
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	other.locker.RLock()
+	defer other.locker.RUnlock()
+	for key, val := range other.mp {
+		s.mp[key] += val
+	}
+}
+
+// Histogram 各计数的项数, 计数 -> 项数
+func (s *StringCounter) Histogram() map[int]int {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+	ret := make(map[int]int)
+	for _, val := range s.mp {
+		ret[val]++
+	}
+	return ret
+}
+
+// Prune 删除计数不大于maxCount的项, 返回被删除的项
+func (s *StringCounter) Prune(maxCount int) []StringCounterItem {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	var ret []StringCounterItem
+	for key, val := range s.mp {
+		if val <= maxCount {
+			ret = append(ret, StringCounterItem{
+				Key:   key,
+				Value: val,
+			})
+			delete(s.mp, key)
+		}
+	}
+	return ret
+}
+
 // Iter iterate StringCounter items
 func (s *StringCounter) Iter() <-chan StringCounterItem {
 	s.locker.RLock()
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// CountMinSketch 近似计数, 内存固定为width*depth个计数, 计数只会高估, 非线程安全
+type CountMinSketch struct {
+	width int
+	depth int
+	table [][]uint32
+}
+
+// NewCountMinSketch 新建CountMinSketch, width越大误差越小, depth越大误差超出范围的概率越小
+func NewCountMinSketch(width int, depth int) *CountMinSketch {
+	if width < 1 {
+		width = 1
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	table := make([][]uint32, depth)
+	for idx := range table {
+		table[idx] = make([]uint32, width)
+	}
+	return &CountMinSketch{
+		width: width,
+		depth: depth,
+		table: table,
+	}
+}
+
+// Width 每行计数个数
+func (c *CountMinSketch) Width() int {
+	return c.width
+}
+
+// Depth 哈希函数个数
+func (c *CountMinSketch) Depth() int {
+	return c.depth
+}
+
+// Add 增加str的计数
+func (c *CountMinSketch) Add(str string, n int) {
+	h1, h2 := sketchHash(str)
+	for i, row := range c.table {
+		row[c.index(h1, h2, i)] += uint32(n)
+	}
+}
+
+// Count 估计str的计数
+func (c *CountMinSketch) Count(str string) int {
+	h1, h2 := sketchHash(str)
+	var ret uint32
+	for i, row := range c.table {
+		if v := row[c.index(h1, h2, i)]; i == 0 || v < ret {
+			ret = v
+		}
+	}
+	return int(ret)
+}
+
+// Clone 复制CountMinSketch
+func (c *CountMinSketch) Clone() *CountMinSketch {
+	table := make([][]uint32, len(c.table))
+	for idx, row := range c.table {
+		table[idx] = append([]uint32(nil), row...)
+	}
+	return &CountMinSketch{
+		width: c.width,
+		depth: c.depth,
+		table: table,
+	}
+}
+
+// Merge 合并另一个相同大小的CountMinSketch
+func (c *CountMinSketch) Merge(other *CountMinSketch) error {
+	if c.width != other.width || c.depth != other.depth {
+		return fmt.Errorf("count-min sketch size mismatch: %dx%d, %dx%d", c.width, c.depth, other.width, other.depth)
+	}
+	for i, row := range other.table {
+		for j, v := range row {
+			c.table[i][j] += v
+		}
+	}
+	return nil
+}
+
+func (c *CountMinSketch) index(h1 uint32, h2 uint32, i int) int {
+	return int((h1 + uint32(i)*h2) % uint32(c.width))
+}
+
+// sketchHash 双重哈希, 由64位fnv哈希拆分为两个32位哈希
+func sketchHash(str string) (uint32, uint32) {
+	h := fnv.New64a()
+	h.Write([]byte(str))
+	sum := h.Sum64()
+	return uint32(sum), uint32(sum>>32) | 1
+}